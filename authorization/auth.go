@@ -1,6 +1,10 @@
 package authorization
 
-import "github.com/viant/mcp-protocol/oauth2/meta"
+import (
+	"github.com/viant/mcp-protocol/authorization/rule"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/schema"
+)
 
 // Token carries authentication credentials.
 type Token struct {
@@ -20,4 +24,22 @@ type Authorization struct {
 	RequiredScopes            []string                        `json:"requiredScopes"`
 	UseIdToken                bool                            `json:"useIdToken,omitempty"`
 	ProtectedResourceMetadata *meta.ProtectedResourceMetadata `json:"protectedResourceMetadata"`
+	// AuthorizationDetails are the RFC 9396 authorization details the token must grant.
+	AuthorizationDetails []*AuthorizationDetail `json:"authorizationDetails,omitempty"`
+	// Rules are argument-level conditions that must all hold before the tool runs.
+	Rules []*rule.Rule `json:"rules,omitempty"`
+}
+
+// Authorize evaluates Rules against the caller's claims and the tool call; a denied call
+// returns a *rule.Denial explaining which rule failed.
+func (a *Authorization) Authorize(claims map[string]any, params *schema.CallToolRequestParams) error {
+	if a == nil || len(a.Rules) == 0 {
+		return nil
+	}
+	input := &rule.Input{Claims: claims}
+	if params != nil {
+		input.Tool = params.Name
+		input.Arguments = params.Arguments
+	}
+	return rule.Evaluate(a.Rules, input)
 }
@@ -0,0 +1,21 @@
+package authorization
+
+import "context"
+
+// TokenFromContext returns the Token stored in ctx under TokenKey.
+func TokenFromContext(ctx context.Context) (*Token, bool) {
+	switch token := ctx.Value(TokenKey).(type) {
+	case *Token:
+		return token, token != nil && token.Token != ""
+	case Token:
+		return &token, token.Token != ""
+	case string:
+		return &Token{Token: token}, token != ""
+	}
+	return nil, false
+}
+
+// WithToken returns a copy of ctx carrying token under TokenKey.
+func WithToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, TokenKey, token)
+}
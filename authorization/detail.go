@@ -0,0 +1,160 @@
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// AuthorizationDetailsParameter is the request parameter, token response member and token claim
+// carrying authorization details (RFC 9396 §2, §7, §9).
+const AuthorizationDetailsParameter = "authorization_details"
+
+// ErrInsufficientAuthorizationDetails is returned when granted authorization details do not cover a requirement.
+var ErrInsufficientAuthorizationDetails = errors.New("insufficient authorization_details")
+
+// AuthorizationDetail is an RFC 9396 authorization details object. Type-specific members are kept in Extra.
+type AuthorizationDetail struct {
+	Type       string         `json:"type"`
+	Locations  []string       `json:"locations,omitempty"`
+	Actions    []string       `json:"actions,omitempty"`
+	Datatypes  []string       `json:"datatypes,omitempty"`
+	Identifier string         `json:"identifier,omitempty"`
+	Privileges []string       `json:"privileges,omitempty"`
+	Extra      map[string]any `json:"-"`
+}
+
+// Covers reports whether the detail grants everything required: the same type and identifier, and a
+// superset of the required locations, actions, datatypes and privileges. Extra members must be equal.
+func (d *AuthorizationDetail) Covers(required *AuthorizationDetail) bool {
+	if d.Type != required.Type {
+		return false
+	}
+	if required.Identifier != "" && d.Identifier != required.Identifier {
+		return false
+	}
+	if !subset(required.Locations, d.Locations) || !subset(required.Actions, d.Actions) ||
+		!subset(required.Datatypes, d.Datatypes) || !subset(required.Privileges, d.Privileges) {
+		return false
+	}
+	for k, v := range required.Extra {
+		expected, _ := json.Marshal(v)
+		actual, _ := json.Marshal(d.Extra[k])
+		if string(expected) != string(actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON keeps type-specific members in Extra.
+func (d *AuthorizationDetail) UnmarshalJSON(data []byte) error {
+	type alias AuthorizationDetail
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	extra, err := meta.ExtractExtra(data, reflect.TypeOf(a))
+	if err != nil {
+		return err
+	}
+	*d = AuthorizationDetail(a)
+	if len(extra) > 0 {
+		d.Extra = extra
+	}
+	return nil
+}
+
+// MarshalJSON writes type-specific members from Extra; declared members take precedence.
+func (d AuthorizationDetail) MarshalJSON() ([]byte, error) {
+	type alias AuthorizationDetail
+	core, err := json.Marshal(alias(d))
+	if err != nil {
+		return nil, err
+	}
+	return meta.MergeExtra(core, d.Extra)
+}
+
+// CheckAuthorizationDetails verifies that granted details cover every detail required by the authorization.
+func (a *Authorization) CheckAuthorizationDetails(granted []*AuthorizationDetail) error {
+	if a == nil {
+		return nil
+	}
+	var missing []string
+	for _, required := range a.AuthorizationDetails {
+		covered := false
+		for _, candidate := range granted {
+			if candidate != nil && candidate.Covers(required) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			data, _ := json.Marshal(required)
+			missing = append(missing, string(data))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInsufficientAuthorizationDetails, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GrantedAuthorizationDetails returns the authorization_details of access token claims or an
+// introspection response.
+func GrantedAuthorizationDetails(claims map[string]any) ([]*AuthorizationDetail, error) {
+	value, ok := claims[AuthorizationDetailsParameter]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*AuthorizationDetail
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", AuthorizationDetailsParameter, err)
+	}
+	return ret, nil
+}
+
+// EncodeAuthorizationDetails encodes details as the authorization_details request parameter.
+func EncodeAuthorizationDetails(details []*AuthorizationDetail) (string, error) {
+	if len(details) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", AuthorizationDetailsParameter, err)
+	}
+	return string(data), nil
+}
+
+type authorizationDetailsKey struct{}
+
+// WithAuthorizationDetails returns a context requesting details from token sources, e.g. for the
+// tool about to be called.
+func WithAuthorizationDetails(ctx context.Context, details ...*AuthorizationDetail) context.Context {
+	return context.WithValue(ctx, authorizationDetailsKey{}, details)
+}
+
+// AuthorizationDetailsFromContext returns the details stored by WithAuthorizationDetails.
+func AuthorizationDetailsFromContext(ctx context.Context) []*AuthorizationDetail {
+	ret, _ := ctx.Value(authorizationDetailsKey{}).([]*AuthorizationDetail)
+	return ret
+}
+
+func subset(required, granted []string) bool {
+	for _, value := range required {
+		if !slices.Contains(granted, value) {
+			return false
+		}
+	}
+	return true
+}
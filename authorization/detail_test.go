@@ -0,0 +1,44 @@
+package authorization
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorization_CheckAuthorizationDetails(t *testing.T) {
+	auth := &Authorization{AuthorizationDetails: []*AuthorizationDetail{
+		{Type: "database_query", Actions: []string{"read"}, Locations: []string{"https://db.example.com"}, Extra: map[string]any{"schema": "sales"}},
+	}}
+	testCases := []struct {
+		name      string
+		claims    string
+		expectErr bool
+	}{
+		{name: "covered", claims: `{"authorization_details":[{"type":"database_query","actions":["read","write"],"locations":["https://db.example.com"],"schema":"sales"}]}`},
+		{name: "missing action", claims: `{"authorization_details":[{"type":"database_query","actions":["write"],"locations":["https://db.example.com"],"schema":"sales"}]}`, expectErr: true},
+		{name: "other type", claims: `{"authorization_details":[{"type":"payment","actions":["read"]}]}`, expectErr: true},
+		{name: "extra mismatch", claims: `{"authorization_details":[{"type":"database_query","actions":["read"],"locations":["https://db.example.com"],"schema":"hr"}]}`, expectErr: true},
+		{name: "none granted", claims: `{}`, expectErr: true},
+	}
+	for _, tc := range testCases {
+		var claims map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(tc.claims), &claims), tc.name)
+		granted, err := GrantedAuthorizationDetails(claims)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		err = auth.CheckAuthorizationDetails(granted)
+		if tc.expectErr {
+			assert.True(t, errors.Is(err, ErrInsufficientAuthorizationDetails), tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+
+	encoded, err := EncodeAuthorizationDetails(auth.AuthorizationDetails)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"database_query","actions":["read"],"locations":["https://db.example.com"],"schema":"sales"}]`, encoded)
+}
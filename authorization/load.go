@@ -0,0 +1,53 @@
+package authorization
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParsePolicy decodes a policy from JSON or YAML and validates its structure.
+// YAML documents use the same member names as JSON (e.g. requiredScopes, protectedResourceMetadata).
+func ParsePolicy(data []byte) (*Policy, error) {
+	ret, err := decodePolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := ret.Validate(nil); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// decodePolicy decodes a policy from JSON or YAML without validating it.
+func decodePolicy(data []byte) (*Policy, error) {
+	var document any
+	// YAML 1.2 is a superset of JSON, so a single decoder handles both formats.
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+	normalized, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+	ret := &Policy{}
+	if err := json.Unmarshal(normalized, ret); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+	return ret, nil
+}
+
+// LoadPolicy reads and validates a JSON or YAML policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+	ret, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ret, nil
+}
@@ -0,0 +1,121 @@
+package authorization
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Tool returns the authorization for the tool name. An exact entry takes precedence over glob
+// patterns; among matching patterns the most specific (longest literal prefix) wins.
+func (a *Policy) Tool(name string) *Authorization {
+	if a == nil {
+		return nil
+	}
+	if a.Global != nil {
+		return a.Global
+	}
+	if auth, ok := a.Tools[name]; ok {
+		return auth
+	}
+	return bestMatch(a.Tools, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// Resource returns the authorization for the resource URI. An exact entry takes precedence over
+// URI templates; among matching templates the most specific (longest literal prefix) wins.
+func (a *Policy) Resource(uri string) *Authorization {
+	if a == nil {
+		return nil
+	}
+	if a.Global != nil {
+		return a.Global
+	}
+	if auth, ok := a.Resources[uri]; ok {
+		return auth
+	}
+	return bestMatch(a.Resources, func(template string) bool {
+		expr, err := uriTemplate(template)
+		return err == nil && expr.MatchString(uri)
+	})
+}
+
+// bestMatch returns the entry of the most specific matching pattern; ties resolve to the lexically smallest pattern.
+func bestMatch(entries map[string]*Authorization, match func(pattern string) bool) *Authorization {
+	var ret *Authorization
+	var bestPattern string
+	best := -1
+	for pattern, auth := range entries {
+		if !match(pattern) {
+			continue
+		}
+		prefix := literalPrefix(pattern)
+		if prefix > best || (prefix == best && pattern < bestPattern) {
+			ret, best, bestPattern = auth, prefix, pattern
+		}
+	}
+	return ret
+}
+
+// literalPrefix returns the length of the pattern before its first wildcard or template expression.
+func literalPrefix(pattern string) int {
+	if i := strings.IndexAny(pattern, "*?[{\\"); i >= 0 {
+		return i
+	}
+	return len(pattern)
+}
+
+var (
+	templateExpression = regexp.MustCompile(`\{([+#./;?&]?)([A-Za-z0-9_.,%*]+)\}`)
+	uriTemplates       = syncmap.NewMap[string, *regexp.Regexp]()
+)
+
+// uriTemplate returns the cached compiled form of a URI template.
+func uriTemplate(template string) (*regexp.Regexp, error) {
+	if expr, ok := uriTemplates.Get(template); ok {
+		return expr, nil
+	}
+	expr, err := compileURITemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	uriTemplates.Put(template, expr)
+	return expr, nil
+}
+
+// compileURITemplate converts an RFC 6570 URI template into an anchored regular expression.
+// Simple expressions ({var}) match a single path segment; reserved expansions ({+var}, {#var})
+// match any remainder including "/".
+func compileURITemplate(template string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range templateExpression.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[last:loc[0]]
+		if strings.ContainsAny(literal, "{}") {
+			return nil, fmt.Errorf("invalid URI template %q", template)
+		}
+		expr.WriteString(regexp.QuoteMeta(literal))
+		switch template[loc[2]:loc[3]] {
+		case "+", "#":
+			expr.WriteString("(.*)")
+		case "/", ".", ";", "?", "&":
+			expr.WriteString(regexp.QuoteMeta(template[loc[2]:loc[3]]) + "?([^?#]*)")
+		default:
+			expr.WriteString("([^/?#]+)")
+		}
+		last = loc[1]
+	}
+	literal := template[last:]
+	if strings.ContainsAny(literal, "{}") {
+		return nil, fmt.Errorf("invalid URI template %q", template)
+	}
+	expr.WriteString(regexp.QuoteMeta(literal))
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
@@ -1,5 +1,7 @@
 package authorization
 
+import "github.com/viant/mcp-protocol/oauth2/meta"
+
 // Policy holds OAuth2/OIDC configuration for fine-grained control.
 
 type Policy struct {
@@ -7,9 +9,9 @@ type Policy struct {
 	Global *Authorization `json:"global,omitempty"`
 	// ExcludeURI skips middleware on matching paths
 	ExcludeURI string `json:"excludeURI,omitempty"`
-	// Per-tool authorization metadata
+	// Per-tool authorization metadata keyed by tool name or glob pattern (e.g. "db_*")
 	Tools map[string]*Authorization `json:"tools,omitempty"`
-	// Per-tenant authorization metadata (reserved for future use)
+	// Per-resource authorization metadata keyed by resource URI or URI template (e.g. "file:///{+path}")
 	Resources map[string]*Authorization `json:"resources,omitempty"`
 }
 
@@ -24,3 +26,22 @@ func (a *Policy) IsFineGrained() bool {
 	}
 	return len(a.Tools) > 0 || len(a.Resources) > 0
 }
+
+// MetadataHandler returns a handler serving the protected resource metadata of the global
+// authorization and of every tool and resource entry at its well-known path.
+func (a *Policy) MetadataHandler(options ...meta.HandlerOption) (*meta.Handler, error) {
+	var resources []*meta.ProtectedResourceMetadata
+	if a != nil {
+		if a.Global != nil {
+			resources = append(resources, a.Global.ProtectedResourceMetadata)
+		}
+		for _, entries := range []map[string]*Authorization{a.Tools, a.Resources} {
+			for _, name := range sortedKeys(entries) {
+				if entry := entries[name]; entry != nil {
+					resources = append(resources, entry.ProtectedResourceMetadata)
+				}
+			}
+		}
+	}
+	return meta.NewProtectedResourceHandler(resources, options...)
+}
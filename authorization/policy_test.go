@@ -0,0 +1,134 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/schema"
+)
+
+const testPolicy = `
+tools:
+  "db_*":
+    requiredScopes: [db.read]
+    protectedResourceMetadata:
+      resource: https://mcp.example.com/tools/db
+      scopes_supported: [db.read, db.write]
+    rules:
+      - name: tenant-tables
+        expr: args.table in vars.tables[claims.tenant]
+        vars:
+          tables:
+            acme: [orders]
+  db_write:
+    requiredScopes: [db.write]
+    protectedResourceMetadata:
+      resource: https://mcp.example.com/tools/db_write
+resources:
+  "file:///{+path}":
+    requiredScopes: [files]
+    protectedResourceMetadata:
+      resource: https://mcp.example.com/files
+  "mem://{tenant}/notes":
+    protectedResourceMetadata:
+      resource: https://mcp.example.com/notes
+`
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if !assert.NoError(t, err) {
+		return
+	}
+	testCases := []struct {
+		name     string
+		lookup   func() *Authorization
+		expected string
+	}{
+		{name: "exact tool", lookup: func() *Authorization { return policy.Tool("db_write") }, expected: "https://mcp.example.com/tools/db_write"},
+		{name: "glob tool", lookup: func() *Authorization { return policy.Tool("db_query") }, expected: "https://mcp.example.com/tools/db"},
+		{name: "unmatched tool", lookup: func() *Authorization { return policy.Tool("search") }},
+		{name: "reserved template", lookup: func() *Authorization { return policy.Resource("file:///etc/hosts") }, expected: "https://mcp.example.com/files"},
+		{name: "simple template", lookup: func() *Authorization { return policy.Resource("mem://acme/notes") }, expected: "https://mcp.example.com/notes"},
+		{name: "simple template segment", lookup: func() *Authorization { return policy.Resource("mem://acme/x/notes") }},
+	}
+	for _, tc := range testCases {
+		actual := tc.lookup()
+		if tc.expected == "" {
+			assert.Nil(t, actual, tc.name)
+			continue
+		}
+		if assert.NotNil(t, actual, tc.name) {
+			assert.Equal(t, tc.expected, actual.ProtectedResourceMetadata.Resource, tc.name)
+		}
+	}
+
+	assert.NoError(t, policy.Validate([]string{"db_query", "db_write"}))
+	assert.Error(t, policy.Validate([]string{"search"}))
+
+	query := policy.Tool("db_query")
+	claims := map[string]any{"tenant": "acme"}
+	assert.NoError(t, query.Authorize(claims, &schema.CallToolRequestParams{Name: "db_query", Arguments: map[string]any{"table": "orders"}}))
+	assert.Error(t, query.Authorize(claims, &schema.CallToolRequestParams{Name: "db_query", Arguments: map[string]any{"table": "payments"}}))
+	assert.Error(t, policy.Validate([]string{"search"}))
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		valid     bool
+		expectErr error
+	}{
+		{name: "json", valid: true, input: `{"global":{"requiredScopes":["a"],"protectedResourceMetadata":{"resource":"https://mcp.example.com","scopes_supported":["a"]}}}`},
+		{name: "global and tools", input: `{"global":{"protectedResourceMetadata":{"resource":"r"}},"tools":{"x":{"protectedResourceMetadata":{"resource":"r"}}}}`, expectErr: ErrConflictingPolicy},
+		{name: "unsupported scope", input: `{"tools":{"x":{"requiredScopes":["b"],"protectedResourceMetadata":{"resource":"r","scopes_supported":["a"]}}}}`},
+		{name: "bad pattern", input: `{"tools":{"x[":{"protectedResourceMetadata":{"resource":"r"}}}}`},
+		{name: "bad template", input: `{"resources":{"file:///{path":{"protectedResourceMetadata":{"resource":"r"}}}}`},
+		{name: "bad rule", input: `{"tools":{"x":{"rules":[{"expr":"args.x in"}],"protectedResourceMetadata":{"resource":"r"}}}}`},
+		{name: "missing metadata", input: `{"tools":{"x":{"requiredScopes":["a"]}}}`},
+		{name: "rules without metadata", valid: true, input: `{"tools":{"x":{"rules":[{"expr":"args.x == 1"}]}}}`},
+	}
+	for _, tc := range testCases {
+		_, err := ParsePolicy([]byte(tc.input))
+		if tc.valid {
+			assert.NoError(t, err, tc.name)
+			continue
+		}
+		if assert.Error(t, err, tc.name) && tc.expectErr != nil {
+			assert.True(t, errors.Is(err, tc.expectErr), tc.name)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher, err := Watch(ctx, path, WithWatchInterval(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, watcher.Policy().Tool("db_query"))
+
+	// an invalid update keeps the previous policy
+	assert.NoError(t, os.WriteFile(path, []byte(`{"tools":{"x[":{}}}`), 0o600))
+	_, err = watcher.Reload()
+	if assert.Error(t, err) {
+		// each problem is reported once
+		assert.Equal(t, 1, strings.Count(err.Error(), "invalid pattern"))
+	}
+	assert.NotNil(t, watcher.Policy().Tool("db_query"))
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"global":{"protectedResourceMetadata":{"resource":"https://mcp.example.com"}}}`), 0o600))
+	changed, err := watcher.Reload()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "https://mcp.example.com", watcher.Policy().Tool("anything").ProtectedResourceMetadata.Resource)
+}
@@ -0,0 +1,18 @@
+// Package rule implements argument-level authorization rules for MCP tool calls.
+//
+// A rule is a boolean expression evaluated before a tool runs over the caller's
+// token claims ("claims"), the tool call arguments ("args"), the tool name
+// ("tool") and the rule's own variables ("vars"):
+//
+//	args.table in vars.tables[claims.tenant] && !(args.table matches "^tmp_")
+//
+// Supported operators are ||, &&, !, ==, !=, <, <=, >, >=, in, prefix,
+// suffix, contains and matches (regular expression); operands are string,
+// number, boolean and null literals, [lists], and member paths with .name or
+// [expression] access. A missing member evaluates to null. On a string, in and
+// contains match whole space-delimited tokens, so claims.scope contains "write"
+// holds for "read write" but not for "read writer". A denied call
+// reports which rule failed and why, e.g.
+//
+//	rule "tenant-tables" denied: args.table ("payments") in vars.tables[claims.tenant] (["orders"]) is false
+package rule
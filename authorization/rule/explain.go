@@ -0,0 +1,51 @@
+package rule
+
+// explain describes why expr evaluated to false in env.
+func explain(expr Expr, env map[string]any) string {
+	switch e := expr.(type) {
+	case *comparison:
+		return annotate(e, env) + " is false"
+	case *group:
+		return explain(e.expr, env)
+	case *not:
+		if inner, ok := unwrap(e.operand).(*comparison); ok {
+			return annotate(inner, env) + " is true"
+		}
+		return e.operand.String() + " is true"
+	case *logical:
+		if e.op == "||" {
+			return explain(e.left, env) + " and " + explain(e.right, env)
+		}
+		if left, _ := evalBool(e.left, env); !left {
+			return explain(e.left, env)
+		}
+		return explain(e.right, env)
+	}
+	return operand(expr, env) + " is false"
+}
+
+// annotate formats a comparison with the values of its non-literal operands.
+func annotate(c *comparison, env map[string]any) string {
+	return operand(c.left, env) + " " + c.op + " " + operand(c.right, env)
+}
+
+func operand(expr Expr, env map[string]any) string {
+	if _, ok := expr.(*literal); ok {
+		return expr.String()
+	}
+	value, err := expr.Eval(env)
+	if err != nil {
+		return expr.String()
+	}
+	return expr.String() + " (" + describe(value) + ")"
+}
+
+func unwrap(expr Expr) Expr {
+	for {
+		g, ok := expr.(*group)
+		if !ok {
+			return expr
+		}
+		expr = g.expr
+	}
+}
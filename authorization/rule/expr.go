@@ -0,0 +1,357 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Expr is a compiled rule expression.
+type Expr interface {
+	// Eval evaluates the expression against env, whose keys are the root names.
+	Eval(env map[string]any) (any, error)
+	// String returns the source form of the expression.
+	String() string
+}
+
+type (
+	literal struct {
+		value any
+		text  string
+	}
+	path struct {
+		root     string
+		segments []Expr
+		dotted   []bool
+	}
+	list struct {
+		items []Expr
+	}
+	group struct {
+		expr Expr
+	}
+	not struct {
+		operand Expr
+	}
+	logical struct {
+		op          string
+		left, right Expr
+	}
+	comparison struct {
+		op          string
+		left, right Expr
+		// pattern is the compiled literal operand of "matches"; dynamic patterns are compiled per evaluation
+		pattern *regexp.Regexp
+	}
+)
+
+func (l *literal) Eval(map[string]any) (any, error) { return l.value, nil }
+func (l *literal) String() string                   { return l.text }
+
+func (p *path) Eval(env map[string]any) (any, error) {
+	value, ok := env[p.root]
+	if !ok {
+		return nil, fmt.Errorf("unknown name %q", p.root)
+	}
+	for _, segment := range p.segments {
+		key, err := segment.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		value = member(value, key)
+	}
+	return value, nil
+}
+
+func (p *path) String() string {
+	var ret strings.Builder
+	ret.WriteString(p.root)
+	for i, segment := range p.segments {
+		if p.dotted[i] {
+			ret.WriteString("." + segment.String())
+			continue
+		}
+		ret.WriteString("[" + segment.String() + "]")
+	}
+	return ret.String()
+}
+
+func (l *list) Eval(env map[string]any) (any, error) {
+	ret := make([]any, len(l.items))
+	for i, item := range l.items {
+		value, err := item.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = value
+	}
+	return ret, nil
+}
+
+func (l *list) String() string {
+	items := make([]string, len(l.items))
+	for i, item := range l.items {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func (g *group) Eval(env map[string]any) (any, error) { return g.expr.Eval(env) }
+func (g *group) String() string                       { return "(" + g.expr.String() + ")" }
+
+func (n *not) Eval(env map[string]any) (any, error) {
+	value, err := evalBool(n.operand, env)
+	return !value, err
+}
+
+func (n *not) String() string { return "!" + n.operand.String() }
+
+func (l *logical) Eval(env map[string]any) (any, error) {
+	left, err := evalBool(l.left, env)
+	if err != nil {
+		return nil, err
+	}
+	if (l.op == "&&" && !left) || (l.op == "||" && left) {
+		return left, nil
+	}
+	return evalBool(l.right, env)
+}
+
+func (l *logical) String() string { return l.left.String() + " " + l.op + " " + l.right.String() }
+
+func (c *comparison) Eval(env map[string]any) (any, error) {
+	left, err := c.left.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+	left, right = normalize(left), normalize(right)
+	switch c.op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		order, err := compare(left, right)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.String(), err)
+		}
+		switch c.op {
+		case "<":
+			return order < 0, nil
+		case "<=":
+			return order <= 0, nil
+		case ">":
+			return order > 0, nil
+		}
+		return order >= 0, nil
+	case "in":
+		return contains(right, left), nil
+	case "contains":
+		return contains(left, right), nil
+	case "prefix", "suffix":
+		value, ok1 := left.(string)
+		affix, ok2 := right.(string)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		if c.op == "prefix" {
+			return strings.HasPrefix(value, affix), nil
+		}
+		return strings.HasSuffix(value, affix), nil
+	case "matches":
+		value, ok1 := left.(string)
+		pattern, ok2 := right.(string)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		expr := c.pattern
+		if expr == nil {
+			var err error
+			if expr, err = compilePattern(pattern); err != nil {
+				return nil, err
+			}
+		}
+		return expr.MatchString(value), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", c.op)
+}
+
+func (c *comparison) String() string { return c.left.String() + " " + c.op + " " + c.right.String() }
+
+// member returns the named member of a map, the indexed element of a list, or nil.
+func member(value, key any) any {
+	switch container := value.(type) {
+	case map[string]any:
+		if name, ok := key.(string); ok {
+			return container[name]
+		}
+	case []any:
+		if index, ok := normalize(key).(float64); ok && index >= 0 && int(index) < len(container) && index == float64(int(index)) {
+			return container[int(index)]
+		}
+	}
+	return nil
+}
+
+// normalize converts Go values to the JSON value model (float64, string, bool, []any, map[string]any).
+func normalize(value any) any {
+	switch v := value.(type) {
+	case nil, bool, string, float64, []any, map[string]any:
+		return value
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []string:
+		ret := make([]any, len(v))
+		for i, item := range v {
+			ret[i] = item
+		}
+		return ret
+	}
+	// round-trip any other structure through JSON
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var ret any
+	if json.Unmarshal(data, &ret) != nil {
+		return value
+	}
+	return ret
+}
+
+func compare(left, right any) (int, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot order %s and %s", describe(left), describe(right))
+}
+
+// contains reports whether container (list, string or map) holds item. A string holds the
+// space-delimited tokens it consists of, e.g. the scopes of a scope claim, not any substring.
+func contains(container, item any) bool {
+	switch c := container.(type) {
+	case []any:
+		for _, candidate := range c {
+			if reflect.DeepEqual(normalize(candidate), item) {
+				return true
+			}
+		}
+	case string:
+		if s, ok := item.(string); ok {
+			return slices.Contains(strings.Fields(c), s)
+		}
+	case map[string]any:
+		if s, ok := item.(string); ok {
+			_, found := c[s]
+			return found
+		}
+	}
+	return false
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return expr, nil
+}
+
+func evalBool(expr Expr, env map[string]any) (bool, error) {
+	value, err := expr.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	ret, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s is %s, not a boolean", expr.String(), describe(value))
+	}
+	return ret, nil
+}
+
+// describe formats a value for deny reasons.
+func describe(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// validate compiles literal regular expressions at parse time, so that invalid ones are reported early
+// and valid ones are compiled once per rule.
+func validate(expr Expr) error {
+	switch e := expr.(type) {
+	case *comparison:
+		if pattern, ok := e.right.(*literal); ok && e.op == "matches" {
+			if s, ok := pattern.value.(string); ok {
+				compiled, err := compilePattern(s)
+				if err != nil {
+					return err
+				}
+				e.pattern = compiled
+			}
+		}
+		if err := validate(e.left); err != nil {
+			return err
+		}
+		return validate(e.right)
+	case *logical:
+		if err := validate(e.left); err != nil {
+			return err
+		}
+		return validate(e.right)
+	case *not:
+		return validate(e.operand)
+	case *group:
+		return validate(e.expr)
+	case *list:
+		for _, item := range e.items {
+			if err := validate(item); err != nil {
+				return err
+			}
+		}
+	case *path:
+		for _, segment := range e.segments {
+			if err := validate(segment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
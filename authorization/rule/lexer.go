@@ -0,0 +1,96 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// operators lists symbolic operators, longest first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!"}
+
+func tokenize(src string) ([]token, error) {
+	var ret []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && rune(src[end]) != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			literal := src[i : end+1]
+			if c == '\'' {
+				literal = `"` + strings.ReplaceAll(strings.ReplaceAll(literal[1:len(literal)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			value, err := strconv.Unquote(literal)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			ret = append(ret, token{kind: tokenString, text: src[i : end+1], value: value, pos: i})
+			i = end + 1
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			end := i + 1
+			for end < len(src) && (unicode.IsDigit(rune(src[end])) || src[end] == '.' || src[end] == 'e' || src[end] == 'E') {
+				end++
+			}
+			value, err := strconv.ParseFloat(src[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number at %d: %w", i, err)
+			}
+			ret = append(ret, token{kind: tokenNumber, text: src[i:end], value: value, pos: i})
+			i = end
+		case unicode.IsLetter(c) || c == '_' || c == '$':
+			end := i + 1
+			for end < len(src) && (unicode.IsLetter(rune(src[end])) || unicode.IsDigit(rune(src[end])) || src[end] == '_' || src[end] == '$') {
+				end++
+			}
+			ret = append(ret, token{kind: tokenIdent, text: src[i:end], pos: i})
+			i = end
+		case strings.ContainsRune("()[].,", c):
+			ret = append(ret, token{kind: tokenPunct, text: string(c), pos: i})
+			i++
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					ret = append(ret, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+		}
+	}
+	return append(ret, token{kind: tokenEOF, pos: len(src)}), nil
+}
@@ -0,0 +1,195 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// word operators usable between operands.
+var wordOperators = map[string]bool{"in": true, "prefix": true, "suffix": true, "contains": true, "matches": true}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse compiles a rule expression.
+func Parse(src string) (Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	ret, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", next.text, next.pos)
+	}
+	if err := validate(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	ret := p.tokens[p.pos]
+	if ret.kind != tokenEOF {
+		p.pos++
+	}
+	return ret
+}
+
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.accept(kind, text) {
+		t := p.peek()
+		return fmt.Errorf("expected %q at %d, got %q", text, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "&&") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (Expr, error) {
+	if p.accept(tokenOperator, "!") {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &not{operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	isComparison := t.kind == tokenOperator && t.text != "||" && t.text != "&&" && t.text != "!"
+	if !isComparison && !(t.kind == tokenIdent && wordOperators[t.text]) {
+		return left, nil
+	}
+	p.next()
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return &comparison{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) operand() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString, tokenNumber:
+		return &literal{value: t.value, text: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literal{value: true, text: t.text}, nil
+		case "false":
+			return &literal{value: false, text: t.text}, nil
+		case "null":
+			return &literal{value: nil, text: t.text}, nil
+		}
+		if wordOperators[t.text] {
+			return nil, fmt.Errorf("unexpected operator %q at %d", t.text, t.pos)
+		}
+		return p.path(&path{root: t.text})
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			ret, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			return &group{expr: ret}, p.expect(tokenPunct, ")")
+		case "[":
+			ret := &list{}
+			for !p.accept(tokenPunct, "]") {
+				if len(ret.items) > 0 {
+					if err := p.expect(tokenPunct, ","); err != nil {
+						return nil, err
+					}
+				}
+				item, err := p.or()
+				if err != nil {
+					return nil, err
+				}
+				ret.items = append(ret.items, item)
+			}
+			return ret, nil
+		}
+	}
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+func (p *parser) path(ret *path) (Expr, error) {
+	for {
+		switch {
+		case p.accept(tokenPunct, "."):
+			t := p.next()
+			if t.kind != tokenIdent {
+				return nil, fmt.Errorf("expected member name at %d", t.pos)
+			}
+			ret.segments = append(ret.segments, &literal{value: t.text, text: t.text})
+			ret.dotted = append(ret.dotted, true)
+		case p.accept(tokenPunct, "["):
+			index, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, "]"); err != nil {
+				return nil, err
+			}
+			ret.segments = append(ret.segments, index)
+			ret.dotted = append(ret.dotted, false)
+		default:
+			return ret, nil
+		}
+	}
+}
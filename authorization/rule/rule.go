@@ -0,0 +1,103 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Rule is a named boolean expression that must hold for a tool call to proceed.
+type Rule struct {
+	Name string `json:"name,omitempty"`
+	// Expr is the rule expression, e.g. `args.table in vars.tables[claims.tenant]`.
+	Expr string `json:"expr"`
+	// Message is an optional human-readable explanation returned when the rule denies a call.
+	Message string `json:"message,omitempty"`
+	// Vars are constants available to the expression as "vars".
+	Vars map[string]any `json:"vars,omitempty"`
+
+	once     sync.Once
+	compiled Expr
+	err      error
+}
+
+// Input is the context a rule is evaluated in.
+type Input struct {
+	Claims    map[string]any
+	Tool      string
+	Arguments map[string]any
+}
+
+// Denial is returned when a rule does not hold.
+type Denial struct {
+	Rule    string
+	Message string
+	// Reason explains which part of the expression failed, with the values involved.
+	Reason string
+}
+
+// Error implements error.
+func (d *Denial) Error() string {
+	ret := fmt.Sprintf("rule %q denied", d.Rule)
+	if d.Message != "" {
+		ret += ": " + d.Message
+	}
+	return ret + ": " + d.Reason
+}
+
+// Compile parses the expression; it is called implicitly by Evaluate.
+func (r *Rule) Compile() (Expr, error) {
+	r.once.Do(func() {
+		if r.compiled, r.err = Parse(r.Expr); r.err != nil {
+			r.err = fmt.Errorf("rule %q: %w", r.name(), r.err)
+		}
+	})
+	return r.compiled, r.err
+}
+
+// Evaluate returns nil when the rule holds and a *Denial otherwise. Evaluation errors such as
+// type mismatches deny the call as well.
+func (r *Rule) Evaluate(input *Input) error {
+	expr, err := r.Compile()
+	if err != nil {
+		return err
+	}
+	env := map[string]any{
+		"claims": normalize(input.Claims),
+		"args":   normalize(input.Arguments),
+		"tool":   input.Tool,
+		"vars":   normalize(r.Vars),
+	}
+	allowed, err := evalBool(expr, env)
+	if err != nil {
+		return &Denial{Rule: r.name(), Message: r.Message, Reason: err.Error()}
+	}
+	if !allowed {
+		return &Denial{Rule: r.name(), Message: r.Message, Reason: explain(expr, env)}
+	}
+	return nil
+}
+
+func (r *Rule) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Expr
+}
+
+// Evaluate evaluates all rules and returns the first denial.
+func Evaluate(rules []*Rule, input *Input) error {
+	for _, rule := range rules {
+		if err := rule.Evaluate(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsDenial reports whether err is a rule denial and returns it.
+func IsDenial(err error) (*Denial, bool) {
+	var ret *Denial
+	ok := errors.As(err, &ret)
+	return ret, ok
+}
@@ -0,0 +1,65 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Evaluate(t *testing.T) {
+	vars := map[string]any{"tables": map[string]any{"acme": []any{"orders", "customers"}}}
+	testCases := []struct {
+		name         string
+		expr         string
+		claims       map[string]any
+		args         map[string]any
+		expectReason string
+		expectErr    bool
+	}{
+		{name: "in allowed", expr: `args.table in vars.tables[claims.tenant]`, claims: map[string]any{"tenant": "acme"}, args: map[string]any{"table": "orders"}},
+		{
+			name:         "in denied",
+			expr:         `args.table in vars.tables[claims.tenant]`,
+			claims:       map[string]any{"tenant": "acme"},
+			args:         map[string]any{"table": "payments"},
+			expectReason: `args.table ("payments") in vars.tables[claims.tenant] (["orders","customers"]) is false`,
+		},
+		{name: "unknown tenant", expr: `args.table in vars.tables[claims.tenant]`, claims: map[string]any{"tenant": "other"}, args: map[string]any{"table": "orders"}, expectReason: `args.table ("orders") in vars.tables[claims.tenant] (null) is false`},
+		{name: "numeric", expr: `args.limit <= 100 && args.limit > 0`, args: map[string]any{"limit": 10}},
+		{name: "numeric denied", expr: `args.limit <= 100 && args.limit > 0`, args: map[string]any{"limit": 1000.0}, expectReason: `args.limit (1000) <= 100 is false`},
+		{name: "prefix", expr: `args.path prefix "/data/" || claims.role == 'admin'`, args: map[string]any{"path": "/data/x"}},
+		{
+			name:         "prefix denied",
+			expr:         `args.path prefix "/data/" || claims.role == 'admin'`,
+			claims:       map[string]any{"role": "user"},
+			args:         map[string]any{"path": "/etc/passwd"},
+			expectReason: `args.path ("/etc/passwd") prefix "/data/" is false and claims.role ("user") == 'admin' is false`,
+		},
+		{name: "regex negation", expr: `!(args.table matches "^tmp_")`, args: map[string]any{"table": "tmp_1"}, expectReason: `args.table ("tmp_1") matches "^tmp_" is true`},
+		{name: "list literal", expr: `claims.scope contains "write" && tool in ["query", "insert"]`, claims: map[string]any{"scope": "read write"}},
+		{name: "scope token", expr: `claims.scope contains "write"`, claims: map[string]any{"scope": "read writer"}, expectReason: `claims.scope ("read writer") contains "write" is false`},
+		{name: "type mismatch", expr: `args.limit < 10`, args: map[string]any{"limit": "ten"}, expectReason: `args.limit < 10: cannot order "ten" and 10`},
+		{name: "not boolean", expr: `args.table`, args: map[string]any{"table": "x"}, expectReason: `args.table is "x", not a boolean`},
+		{name: "syntax", expr: `args.table in`, expectErr: true},
+		{name: "bad regex", expr: `args.table matches "("`, expectErr: true},
+		{name: "dynamic regex", expr: `args.table matches claims.tables`, claims: map[string]any{"tables": "^acme_"}, args: map[string]any{"table": "acme_orders"}},
+		{name: "dynamic regex denied", expr: `args.table matches claims.tables`, claims: map[string]any{"tables": "^acme_"}, args: map[string]any{"table": "orders"}, expectReason: `args.table ("orders") matches claims.tables ("^acme_") is false`},
+	}
+	for _, tc := range testCases {
+		r := &Rule{Name: "test", Expr: tc.expr, Vars: vars}
+		err := r.Evaluate(&Input{Claims: tc.claims, Arguments: tc.args, Tool: "query"})
+		if tc.expectErr {
+			_, isDenial := IsDenial(err)
+			assert.True(t, err != nil && !isDenial, tc.name)
+			continue
+		}
+		if tc.expectReason == "" {
+			assert.NoError(t, err, tc.name)
+			continue
+		}
+		denial, ok := IsDenial(err)
+		if assert.True(t, ok, tc.name) {
+			assert.Equal(t, tc.expectReason, denial.Reason, tc.name)
+		}
+	}
+}
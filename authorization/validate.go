@@ -0,0 +1,111 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+)
+
+// ErrConflictingPolicy is returned when a policy mixes Global with Tools or Resources.
+var ErrConflictingPolicy = errors.New("global authorization is mutually exclusive with tools/resources")
+
+// Validate checks the policy for conflicts: Global combined with fine-grained entries, malformed
+// tool patterns, resource templates or rule expressions, entries requiring scopes, authorization
+// details or ID tokens without the protected resource metadata that advertises them, and required
+// scopes or authorization details types not advertised by that metadata. Entries carrying only
+// rules need no metadata.
+// When toolNames is not nil, tool entries matching no registered tool are reported as well.
+// All problems are returned joined.
+func (a *Policy) Validate(toolNames []string) error {
+	if a == nil {
+		return nil
+	}
+	var errs []error
+	if a.Global != nil && (len(a.Tools) > 0 || len(a.Resources) > 0) {
+		errs = append(errs, ErrConflictingPolicy)
+	}
+	if a.Global != nil {
+		errs = append(errs, a.Global.validate("global")...)
+	}
+	for _, pattern := range sortedKeys(a.Tools) {
+		name := fmt.Sprintf("tools[%q]", pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid pattern: %w", name, err))
+		} else if toolNames != nil && !matchesAny(pattern, toolNames) {
+			errs = append(errs, fmt.Errorf("%s: matches no registered tool", name))
+		}
+		errs = append(errs, a.Tools[pattern].validate(name)...)
+	}
+	for _, template := range sortedKeys(a.Resources) {
+		name := fmt.Sprintf("resources[%q]", template)
+		if _, err := uriTemplate(template); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		errs = append(errs, a.Resources[template].validate(name)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Authorization) validate(name string) []error {
+	if a == nil {
+		return []error{fmt.Errorf("%s: authorization was empty", name)}
+	}
+	var errs []error
+	for _, r := range a.Rules {
+		if _, err := r.Compile(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	metadata := a.ProtectedResourceMetadata
+	if metadata == nil {
+		// the challenge of an entry requiring a token points clients at its metadata
+		if len(a.RequiredScopes) > 0 || len(a.AuthorizationDetails) > 0 || a.UseIdToken {
+			errs = append(errs, fmt.Errorf("%s: protectedResourceMetadata is required", name))
+		}
+		return errs
+	}
+	if metadata.Resource == "" {
+		errs = append(errs, fmt.Errorf("%s: protectedResourceMetadata.resource is required", name))
+	}
+	for i, detail := range a.AuthorizationDetails {
+		switch {
+		case detail == nil || detail.Type == "":
+			errs = append(errs, fmt.Errorf("%s: authorizationDetails[%d].type is required", name, i))
+		case len(metadata.AuthorizationDetailsTypesSupported) > 0 && !slices.Contains(metadata.AuthorizationDetailsTypesSupported, detail.Type):
+			errs = append(errs, fmt.Errorf("%s: authorization details type %q is not in authorization_details_types_supported", name, detail.Type))
+		}
+	}
+	if len(metadata.ScopesSupported) == 0 {
+		return errs
+	}
+	supported := make(map[string]bool, len(metadata.ScopesSupported))
+	for _, scope := range metadata.ScopesSupported {
+		supported[scope] = true
+	}
+	for _, scope := range a.RequiredScopes {
+		if !supported[scope] {
+			errs = append(errs, fmt.Errorf("%s: required scope %q is not in scopes_supported", name, scope))
+		}
+	}
+	return errs
+}
+
+func matchesAny(pattern string, names []string) bool {
+	for _, name := range names {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(entries map[string]*Authorization) []string {
+	ret := make([]string, 0, len(entries))
+	for key := range entries {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,136 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval at which a Watcher checks the policy file.
+const DefaultWatchInterval = 2 * time.Second
+
+// Watcher keeps a policy loaded from a file up to date. A reload that fails to parse or validate
+// is reported and the previously loaded policy stays in effect.
+type Watcher struct {
+	path      string
+	interval  time.Duration
+	toolNames []string
+	onChange  func(policy *Policy)
+	onError   func(err error)
+	policy    atomic.Pointer[Policy]
+	modTime   time.Time
+	size      int64
+	mux       sync.Mutex
+}
+
+// WatchOption customizes a Watcher.
+type WatchOption func(w *Watcher)
+
+// WithWatchInterval sets how often the file is checked for changes.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithToolNames validates tool entries against the registered tool names.
+func WithToolNames(names ...string) WatchOption {
+	return func(w *Watcher) {
+		w.toolNames = names
+	}
+}
+
+// WithOnChange registers a callback invoked after a changed policy was loaded.
+func WithOnChange(fn func(policy *Policy)) WatchOption {
+	return func(w *Watcher) {
+		w.onChange = fn
+	}
+}
+
+// WithOnError registers a callback invoked when a changed policy cannot be loaded.
+func WithOnError(fn func(err error)) WatchOption {
+	return func(w *Watcher) {
+		w.onError = fn
+	}
+}
+
+// Policy returns the most recently loaded policy.
+func (w *Watcher) Policy() *Policy {
+	return w.policy.Load()
+}
+
+// Reload loads the file when it changed since the last load and reports whether the policy was replaced.
+func (w *Watcher) Reload() (bool, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat policy %s: %w", w.path, err)
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false, nil
+	}
+	policy, err := w.load()
+	// remember the failed version too, so that an invalid file is reported once rather than on every tick
+	w.modTime, w.size = info.ModTime(), info.Size()
+	if err != nil {
+		return false, err
+	}
+	w.policy.Store(policy)
+	return true, nil
+}
+
+// load reads, decodes and validates the policy file once, checking tool entries against toolNames.
+func (w *Watcher) load() (*Policy, error) {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", w.path, err)
+	}
+	ret, err := decodePolicy(data)
+	if err == nil {
+		err = ret.Validate(w.toolNames)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", w.path, err)
+	}
+	return ret, nil
+}
+
+func (w *Watcher) watch(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := w.Reload()
+		if err != nil {
+			if w.onError != nil {
+				w.onError(err)
+			}
+			continue
+		}
+		if changed && w.onChange != nil {
+			w.onChange(w.Policy())
+		}
+	}
+}
+
+// Watch loads the policy file and reloads it on change until ctx is done.
+// The initial load must succeed.
+func Watch(ctx context.Context, path string, options ...WatchOption) (*Watcher, error) {
+	ret := &Watcher{path: path, interval: DefaultWatchInterval}
+	for _, option := range options {
+		option(ret)
+	}
+	if _, err := ret.Reload(); err != nil {
+		return nil, err
+	}
+	go ret.watch(ctx)
+	return ret, nil
+}
@@ -0,0 +1,6 @@
+// Package stub holds the runtime used by typed tool clients generated with cmd/mcpstub.
+//
+// Generated clients call tools through a ToolCaller, typically an adapter over an
+// MCP client connection, encoding typed inputs as call arguments and decoding
+// structured content into typed outputs.
+package stub
@@ -0,0 +1,79 @@
+package stub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/viant/mcp-protocol/schema"
+)
+
+// ToolCaller calls a tool on an MCP server.
+type ToolCaller interface {
+	CallTool(ctx context.Context, params *schema.CallToolRequestParams) (*schema.CallToolResult, error)
+}
+
+// ToolCallerFunc adapts a function to ToolCaller.
+type ToolCallerFunc func(ctx context.Context, params *schema.CallToolRequestParams) (*schema.CallToolResult, error)
+
+// CallTool calls f.
+func (f ToolCallerFunc) CallTool(ctx context.Context, params *schema.CallToolRequestParams) (*schema.CallToolResult, error) {
+	return f(ctx, params)
+}
+
+// ToolError reports a tool result flagged with isError.
+type ToolError struct {
+	Tool   string
+	Result *schema.CallToolResult
+}
+
+// Error returns the text content of the result.
+func (e *ToolError) Error() string {
+	var texts []string
+	for _, content := range e.Result.Content {
+		data, err := json.Marshal(content)
+		if err != nil {
+			continue
+		}
+		var text struct {
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(data, &text) == nil && text.Text != "" {
+			texts = append(texts, text.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return fmt.Sprintf("tool %s failed", e.Tool)
+	}
+	return fmt.Sprintf("tool %s failed: %s", e.Tool, strings.Join(texts, "\n"))
+}
+
+// Call calls the named tool with input encoded as arguments; input may be nil for tools without arguments.
+// When output is not nil, the structured content of the result is decoded into it.
+func Call(ctx context.Context, caller ToolCaller, name string, input interface{}, output interface{}) (*schema.CallToolResult, error) {
+	params := &schema.CallToolRequestParams{Name: name}
+	if input != nil {
+		var err error
+		if params, err = schema.NewCallToolRequestParams(name, input); err != nil {
+			return nil, fmt.Errorf("failed to encode %s arguments: %w", name, err)
+		}
+	}
+	result, err := caller.CallTool(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError != nil && *result.IsError {
+		return result, &ToolError{Tool: name, Result: result}
+	}
+	if output == nil {
+		return result, nil
+	}
+	if result.StructuredContent == nil {
+		return result, fmt.Errorf("tool %s returned no structured content", name)
+	}
+	if err = schema.DecodeArguments(result.StructuredContent, output); err != nil {
+		return result, fmt.Errorf("failed to decode %s output: %w", name, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,8 @@
+// Package stubgen generates typed Go clients for MCP tools.
+//
+// It reverses schema generation: each tool input and output schema from a tools/list
+// result (or an in-process server.Registry) becomes a Go struct, with enums as named
+// string types, nested objects and $defs as named structs, and defaults, choices and
+// validation keywords kept as struct tags. A client method per tool calls it through
+// the client/stub runtime.
+package stubgen
@@ -0,0 +1,537 @@
+package stubgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/viant/mcp-protocol/schema"
+	"github.com/viant/mcp-protocol/server"
+)
+
+// Option customizes a Generator.
+type Option func(*Generator)
+
+// Generator emits typed Go input/output structs and a client wrapper for MCP tools.
+type Generator struct {
+	packageName string
+	clientName  string
+}
+
+// WithPackage sets the package name of the generated file (default "tools").
+func WithPackage(name string) Option {
+	return func(g *Generator) {
+		g.packageName = name
+	}
+}
+
+// WithClientName sets the name of the generated client type (default "Client").
+func WithClientName(name string) Option {
+	return func(g *Generator) {
+		g.clientName = name
+	}
+}
+
+// New creates a Generator.
+func New(options ...Option) *Generator {
+	ret := &Generator{packageName: "tools", clientName: "Client"}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+// GenerateFromJSON generates client stubs from a tools/list result document.
+func (g *Generator) GenerateFromJSON(data []byte) ([]byte, error) {
+	var result schema.ListToolsResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode tools/list result: %w", err)
+	}
+	return g.Generate(result.Tools)
+}
+
+// GenerateFromRegistry generates client stubs for the tools registered in-process.
+func (g *Generator) GenerateFromRegistry(registry *server.Registry) ([]byte, error) {
+	return g.Generate(registry.ListRegisteredTools())
+}
+
+// Generate returns formatted Go source declaring, per tool, <Tool>Input and <Tool>Output types
+// derived from its schemas and a client method calling it.
+func (g *Generator) Generate(tools []schema.Tool) ([]byte, error) {
+	tools = append([]schema.Tool(nil), tools...)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	f := &file{names: map[string]bool{g.clientName: true, "New" + g.clientName: true}, imports: map[string]bool{}}
+	var methods bytes.Buffer
+	for _, tool := range tools {
+		if err := f.tool(&methods, g.clientName, tool); err != nil {
+			return nil, fmt.Errorf("failed to generate tool %s: %w", tool.Name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by mcpstub. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.packageName)
+	f.imports["context"] = true
+	f.imports["github.com/viant/mcp-protocol/client/stub"] = true
+	var standard, external []string
+	for path := range f.imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			external = append(external, strconv.Quote(path))
+		} else {
+			standard = append(standard, strconv.Quote(path))
+		}
+	}
+	sort.Strings(standard)
+	sort.Strings(external)
+	fmt.Fprintf(&out, "import (\n%s\n\n%s\n)\n\n", strings.Join(standard, "\n"), strings.Join(external, "\n"))
+	out.Write(f.types.Bytes())
+	fmt.Fprintf(&out, "// %s calls MCP tools with typed inputs and outputs.\n", g.clientName)
+	fmt.Fprintf(&out, "type %s struct {\n\tcaller stub.ToolCaller\n}\n\n", g.clientName)
+	fmt.Fprintf(&out, "// New%s creates a %s calling tools with caller.\n", g.clientName, g.clientName)
+	fmt.Fprintf(&out, "func New%s(caller stub.ToolCaller) *%s {\n\treturn &%s{caller: caller}\n}\n\n", g.clientName, g.clientName, g.clientName)
+	out.Write(methods.Bytes())
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w", err)
+	}
+	return source, nil
+}
+
+// file accumulates the declarations of a generated file.
+type file struct {
+	types   bytes.Buffer
+	names   map[string]bool
+	imports map[string]bool
+}
+
+// scope resolves $ref pointers within one root schema.
+type scope struct {
+	root     string
+	defs     map[string]interface{}
+	declared map[string]string
+	prefix   string
+}
+
+func (f *file) tool(methods *bytes.Buffer, clientName string, tool schema.Tool) error {
+	base := f.unique(exportedName(tool.Name))
+	description := ""
+	if tool.Description != nil {
+		description = strings.TrimSpace(*tool.Description)
+	}
+
+	input, err := toMap(tool.InputSchema)
+	if err != nil {
+		return err
+	}
+	inputType, err := f.rootType(base+"Input", input, "input of the "+tool.Name+" tool")
+	if err != nil {
+		return err
+	}
+	outputType := ""
+	if tool.OutputSchema != nil {
+		output, err := toMap(tool.OutputSchema)
+		if err != nil {
+			return err
+		}
+		if outputType, err = f.rootType(base+"Output", output, "output of the "+tool.Name+" tool"); err != nil {
+			return err
+		}
+	}
+
+	comment := fmt.Sprintf("%s calls the %s tool.", base, tool.Name)
+	if description != "" {
+		comment += "\n" + description
+	}
+	methods.WriteString(docComment(comment, ""))
+	params, args := "ctx context.Context", "nil"
+	if inputType != "" {
+		params += ", input " + inputType
+		args = "input"
+	}
+	name := strconv.Quote(tool.Name)
+	if outputType == "" {
+		f.imports["github.com/viant/mcp-protocol/schema"] = true
+		fmt.Fprintf(methods, "func (c *%s) %s(%s) (*schema.CallToolResult, error) {\n", clientName, base, params)
+		fmt.Fprintf(methods, "\treturn stub.Call(ctx, c.caller, %s, %s, nil)\n}\n\n", name, args)
+		return nil
+	}
+	fmt.Fprintf(methods, "func (c *%s) %s(%s) (%s, error) {\n", clientName, base, params, outputType)
+	fmt.Fprintf(methods, "\toutput := %s\n", zeroValue(outputType))
+	fmt.Fprintf(methods, "\tif _, err := stub.Call(ctx, c.caller, %s, %s, %s); err != nil {\n\t\treturn nil, err\n\t}\n", name, args, outputTarget(outputType))
+	fmt.Fprintf(methods, "\treturn output, nil\n}\n\n")
+	return nil
+}
+
+// rootType declares the type of a root object schema and returns how methods refer to it,
+// or "" for a schema without properties, i.e. a tool without arguments.
+func (f *file) rootType(name string, root map[string]interface{}, doc string) (string, error) {
+	defs, _ := root["$defs"].(map[string]interface{})
+	s := &scope{root: name, defs: defs, declared: map[string]string{}, prefix: strings.TrimSuffix(strings.TrimSuffix(name, "Input"), "Output")}
+	properties, _ := root["properties"].(map[string]interface{})
+	additional, hasAdditional := root["additionalProperties"].(map[string]interface{})
+	switch {
+	case len(properties) > 0:
+		f.names[name] = true
+		if err := f.declareStruct(s, name, root, fmt.Sprintf("%s is the %s.", name, doc)); err != nil {
+			return "", err
+		}
+		return "*" + name, nil
+	case hasAdditional:
+		f.names[name] = true
+		valueType, err := f.typeOf(s, additional, name+"Value", "value")
+		if err != nil {
+			return "", err
+		}
+		f.types.WriteString(docComment(fmt.Sprintf("%s is the %s.", name, doc), ""))
+		fmt.Fprintf(&f.types, "type %s map[string]%s\n\n", name, valueType)
+		return name, nil
+	case root["additionalProperties"] == true:
+		f.names[name] = true
+		f.types.WriteString(docComment(fmt.Sprintf("%s is the %s.", name, doc), ""))
+		fmt.Fprintf(&f.types, "type %s map[string]interface{}\n\n", name)
+		return name, nil
+	}
+	return "", nil
+}
+
+// declareStruct writes a struct type for an object schema, with fields in property name order,
+// documented by the schema description or doc otherwise.
+func (f *file) declareStruct(s *scope, name string, object map[string]interface{}, doc string) error {
+	properties, _ := object["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if list, ok := object["required"].([]interface{}); ok {
+		for _, item := range list {
+			if value, ok := item.(string); ok {
+				required[value] = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var body bytes.Buffer
+	fields := map[string]bool{}
+	for index, key := range keys {
+		property, _ := properties[key].(map[string]interface{})
+		base := exportedName(key)
+		if base == "" { // e.g. "$" or "-"
+			base = "Field" + strconv.Itoa(index)
+		}
+		fieldName := base
+		for i := 2; fields[fieldName]; i++ {
+			fieldName = base + strconv.Itoa(i)
+		}
+		fields[fieldName] = true
+		fieldType, err := f.typeOf(s, property, name+fieldName, key)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", key, err)
+		}
+		if desc, ok := description(property); ok {
+			body.WriteString(docComment(desc, "\t"))
+		}
+		fmt.Fprintf(&body, "\t%s %s `%s`\n", fieldName, fieldType, fieldTag(key, property, required[key], strings.HasPrefix(fieldType, "*")))
+	}
+
+	if desc, ok := description(object); ok {
+		doc = desc
+	}
+	f.types.WriteString(docComment(doc, ""))
+	fmt.Fprintf(&f.types, "type %s struct {\n%s}\n\n", name, body.String())
+	return nil
+}
+
+// typeOf returns the Go type of a property schema, declaring named types for nested objects and enums.
+func (f *file) typeOf(s *scope, property map[string]interface{}, name, key string) (string, error) {
+	property, nullable := nonNull(property)
+	if ref, ok := property["$ref"].(string); ok {
+		target, err := f.reference(s, ref)
+		if err != nil {
+			return "", err
+		}
+		return "*" + target, nil
+	}
+	typeName, _ := property["type"].(string)
+	if typeName == "" {
+		if _, ok := property["properties"]; ok {
+			typeName = "object"
+		}
+	}
+	var ret string
+	switch typeName {
+	case "string":
+		switch {
+		case property["format"] == "date-time":
+			f.imports["time"] = true
+			ret = "time.Time"
+		case property["contentEncoding"] == "base64":
+			return "[]byte", nil
+		case property["enum"] != nil:
+			ret = f.declareEnum(name, key, property)
+		default:
+			ret = "string"
+		}
+	case "integer":
+		ret = "int"
+	case "number":
+		ret = "float64"
+	case "boolean":
+		ret = "bool"
+	case "array":
+		items, _ := property["items"].(map[string]interface{})
+		itemType, err := f.typeOf(s, items, name+"Item", key)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case "object":
+		if properties, _ := property["properties"].(map[string]interface{}); len(properties) > 0 {
+			name = f.unique(name)
+			if err := f.declareStruct(s, name, property, fmt.Sprintf("%s is the type of the %s property.", name, key)); err != nil {
+				return "", err
+			}
+			return "*" + name, nil
+		}
+		if additional, ok := property["additionalProperties"].(map[string]interface{}); ok && len(additional) > 0 {
+			valueType, err := f.typeOf(s, additional, name+"Value", key)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + valueType, nil
+		}
+		return "map[string]interface{}", nil
+	default:
+		// unions and unconstrained values
+		return "interface{}", nil
+	}
+	if nullable {
+		return "*" + ret, nil
+	}
+	return ret, nil
+}
+
+// reference returns the type name of a $ref target, declaring definitions on first use.
+func (f *file) reference(s *scope, ref string) (string, error) {
+	if ref == "#" {
+		return s.root, nil
+	}
+	defName, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return "", fmt.Errorf("unsupported $ref %s", ref)
+	}
+	if name, ok := s.declared[defName]; ok {
+		return name, nil
+	}
+	def, ok := s.defs[defName].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("undefined $ref %s", ref)
+	}
+	name := f.unique(s.prefix + exportedName(defName))
+	s.declared[defName] = name
+	if err := f.declareStruct(s, name, def, fmt.Sprintf("%s is the %s definition.", name, defName)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// declareEnum writes a named string type with a constant per enum value.
+func (f *file) declareEnum(name, key string, property map[string]interface{}) string {
+	name = f.unique(name)
+	values, _ := property["enum"].([]interface{})
+	f.types.WriteString(docComment(fmt.Sprintf("%s enumerates the allowed values of the %s property.", name, key), ""))
+	fmt.Fprintf(&f.types, "type %s string\n\nconst (\n", name)
+	for i, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		constant := exportedName(text)
+		if constant == "" || !unicode.IsLetter([]rune(constant)[0]) {
+			constant = "Value" + strconv.Itoa(i)
+		}
+		fmt.Fprintf(&f.types, "\t%s %s = %s\n", f.unique(name+constant), name, strconv.Quote(text))
+	}
+	f.types.WriteString(")\n\n")
+	return name
+}
+
+// unique returns name, suffixed with a number if it was already declared.
+func (f *file) unique(name string) string {
+	ret := name
+	for i := 2; f.names[ret]; i++ {
+		ret = name + strconv.Itoa(i)
+	}
+	f.names[ret] = true
+	return ret
+}
+
+// tagKeywords are schema keywords written back as struct tags read by schema generation.
+var tagKeywords = []string{"format", "title", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems", "deprecated"}
+
+// fieldTag returns the struct tag reproducing the property on schema generation.
+func fieldTag(key string, property map[string]interface{}, required, pointer bool) string {
+	property, _ = nonNull(property)
+	jsonTag := key
+	if !required {
+		jsonTag += ",omitempty"
+	} else if key == "-" {
+		jsonTag += "," // a bare "-" would skip the field
+	}
+	tags := []string{"json:" + strconv.Quote(jsonTag)}
+	if required && pointer {
+		tags = append(tags, `required:"true"`)
+	}
+	if desc, ok := description(property); ok {
+		tags = append(tags, "description:"+strconv.Quote(desc))
+	}
+	if values, ok := property["enum"].([]interface{}); ok {
+		for _, value := range values {
+			tags = append(tags, "choice:"+strconv.Quote(fmt.Sprint(value)))
+		}
+	}
+	if def, ok := property["default"]; ok {
+		tags = append(tags, "default:"+strconv.Quote(tagValue(def)))
+	}
+	for _, keyword := range tagKeywords {
+		value, ok := property[keyword]
+		if !ok || (keyword == "format" && (value == "date-time")) {
+			continue
+		}
+		tags = append(tags, keyword+":"+strconv.Quote(tagValue(value)))
+	}
+	ret := strings.Join(tags, " ")
+	if strings.Contains(ret, "`") {
+		// a raw string literal cannot hold a backquote; keep the essential tags
+		return tags[0]
+	}
+	return ret
+}
+
+// tagValue formats a schema value as a struct tag value.
+func tagValue(value interface{}) string {
+	switch actual := value.(type) {
+	case string:
+		return actual
+	case float64:
+		return strconv.FormatFloat(actual, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(actual)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// nonNull unwraps nullable schemas: OpenAPI nullable, ["T","null"] type arrays and anyOf unions with {"type":"null"}.
+func nonNull(property map[string]interface{}) (map[string]interface{}, bool) {
+	if property == nil {
+		return map[string]interface{}{}, false
+	}
+	if property["nullable"] == true {
+		return property, true
+	}
+	if types, ok := property["type"].([]interface{}); ok {
+		var nonNullTypes []interface{}
+		for _, t := range types {
+			if t != "null" {
+				nonNullTypes = append(nonNullTypes, t)
+			}
+		}
+		if len(nonNullTypes) == 1 && len(types) == 2 {
+			ret := copyMap(property)
+			ret["type"] = nonNullTypes[0]
+			return ret, true
+		}
+	}
+	if variants, ok := property["anyOf"].([]interface{}); ok && len(variants) == 2 {
+		for i, variant := range variants {
+			if v, ok := variant.(map[string]interface{}); ok && len(v) == 1 && v["type"] == "null" {
+				other, _ := variants[1-i].(map[string]interface{})
+				ret := copyMap(other)
+				for k, v := range property {
+					if k != "anyOf" {
+						ret[k] = v
+					}
+				}
+				return ret, true
+			}
+		}
+	}
+	return property, false
+}
+
+func copyMap(source map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(source))
+	for k, v := range source {
+		ret[k] = v
+	}
+	return ret
+}
+
+func description(property map[string]interface{}) (string, bool) {
+	desc, ok := property["description"].(string)
+	desc = strings.TrimSpace(desc)
+	return desc, ok && desc != ""
+}
+
+// toMap converts a typed schema into its generic JSON form.
+func toMap(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string]interface{}
+	return ret, json.Unmarshal(data, &ret)
+}
+
+// exportedName converts a tool or property name such as "list_files" into an exported identifier.
+func exportedName(name string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	ret := builder.String()
+	if ret != "" && unicode.IsDigit([]rune(ret)[0]) {
+		ret = "X" + ret
+	}
+	return ret
+}
+
+// docComment formats text as a Go comment with the given indentation.
+func docComment(text, indent string) string {
+	var builder strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		builder.WriteString(indent + "// " + strings.TrimRight(line, " \t") + "\n")
+	}
+	return strings.ReplaceAll(builder.String(), indent+"// \n", indent+"//\n")
+}
+
+func zeroValue(outputType string) string {
+	if strings.HasPrefix(outputType, "*") {
+		return "&" + outputType[1:] + "{}"
+	}
+	return outputType + "{}"
+}
+
+func outputTarget(outputType string) string {
+	if strings.HasPrefix(outputType, "*") {
+		return "output"
+	}
+	return "&output"
+}
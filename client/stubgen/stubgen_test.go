@@ -0,0 +1,101 @@
+package stubgen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/server"
+)
+
+type forecastAddress struct {
+	City    string `json:"city" description:"city name"`
+	Country string `json:"country,omitempty" default:"US"`
+}
+
+type forecastInput struct {
+	Address *forecastAddress `json:"address"`
+	Units   string           `json:"units,omitempty" choice:"metric" choice:"imperial" default:"metric"`
+	Days    int              `json:"days,omitempty" minimum:"1" maximum:"14"`
+	Tags    []string         `json:"tags,omitempty"`
+}
+
+type forecastOutput struct {
+	Temperatures []float64 `json:"temperatures"`
+}
+
+func TestGenerator_GenerateFromRegistry(t *testing.T) {
+	registry := server.NewRegistry()
+	err := server.RegisterTool[*forecastInput, *forecastOutput](registry, "get_forecast", "Returns a weather forecast.", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = server.RegisterNoArgTool[*forecastOutput](registry, "ping", "Checks the service.", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	source, err := New(WithPackage("weather")).GenerateFromRegistry(registry)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "weather.go", source, 0)
+	assert.NoError(t, err, string(source))
+	code := strings.Join(strings.Fields(string(source)), " ")
+	for _, expected := range []string{
+		"package weather",
+		"type GetForecastInput struct",
+		"type GetForecastInputUnits string",
+		`GetForecastInputUnitsMetric GetForecastInputUnits = "metric"`,
+		"`json:\"units,omitempty\" choice:\"metric\" choice:\"imperial\" default:\"metric\"`",
+		"`json:\"days,omitempty\" minimum:\"1\" maximum:\"14\"`",
+		"`json:\"country,omitempty\" default:\"US\"`",
+		"Address *GetForecastInputAddress",
+		"type GetForecastInputAddress struct",
+		"func (c *Client) GetForecast(ctx context.Context, input *GetForecastInput) (*GetForecastOutput, error)",
+		"func (c *Client) Ping(ctx context.Context) (*PingOutput, error)",
+	} {
+		assert.Contains(t, code, expected)
+	}
+}
+
+func TestGenerator_GenerateFromJSON(t *testing.T) {
+	document := `{"tools":[{"name":"find_nodes","inputSchema":{"type":"object",
+		"$schema":"https://json-schema.org/draft/2020-12/schema",
+		"properties":{
+			"root":{"$ref":"#/$defs/node"},
+			"since":{"type":["string","null"],"format":"date-time"},
+			"limit":{"type":["integer","null"]},
+			"labels":{"type":"object","additionalProperties":{"type":"string"}},
+			"$":{"type":"string"},
+			"-":{"type":"boolean"}
+		},
+		"required":["root","since","-"],
+		"$defs":{"node":{"type":"object","properties":{
+			"name":{"type":"string"},
+			"children":{"type":"array","items":{"$ref":"#/$defs/node"}}
+		},"required":["name"]}}}}]}`
+	source, err := New().GenerateFromJSON([]byte(document))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "tools.go", source, 0)
+	assert.NoError(t, err, string(source))
+	code := strings.Join(strings.Fields(string(source)), " ")
+	for _, expected := range []string{
+		"package tools",
+		"\"time\"",
+		"type FindNodesNode struct",
+		"[]*FindNodesNode `json:\"children,omitempty\"`",
+		"*FindNodesNode `json:\"root\" required:\"true\"`",
+		"*time.Time `json:\"since\" required:\"true\"`",
+		"*int `json:\"limit,omitempty\"`",
+		"map[string]string `json:\"labels,omitempty\"`",
+		"Field0 string `json:\"$,omitempty\"`",
+		"Field1 bool `json:\"-,\"`",
+		"func (c *Client) FindNodes(ctx context.Context, input *FindNodesInput) (*schema.CallToolResult, error)",
+	} {
+		assert.Contains(t, code, expected)
+	}
+}
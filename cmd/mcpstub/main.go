@@ -0,0 +1,50 @@
+// Command mcpstub writes typed Go client stubs for the tools of an MCP server,
+// read from a tools/list result JSON document.
+//
+// Typical use:
+//
+//	mcpstub -in tools.json -pkg weather -o weather_client.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/viant/mcp-protocol/client/stubgen"
+)
+
+func main() {
+	input := flag.String("in", "", "tools/list result JSON file (default: stdin)")
+	pkgName := flag.String("pkg", "tools", "package name of the generated file")
+	clientName := flag.String("client", "Client", "name of the generated client type")
+	output := flag.String("o", "", "output file (default: stdout)")
+	flag.Parse()
+	if err := run(*input, *pkgName, *clientName, *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(input, pkgName, clientName, output string) error {
+	var data []byte
+	var err error
+	if input == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+	source, err := stubgen.New(stubgen.WithPackage(pkgName), stubgen.WithClientName(clientName)).GenerateFromJSON(data)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(output, source, 0o644)
+}
@@ -0,0 +1,59 @@
+// Command schemadoc writes a Go file registering the type and field doc comments of a package
+// with schema.RegisterDescriptions, so that generated tool schemas carry them without source access.
+//
+// Typical use from the package declaring tool input types:
+//
+//	//go:generate go run github.com/viant/mcp-protocol/cmd/schemadoc
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/build"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/viant/mcp-protocol/schema"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package source directory")
+	pkgPath := flag.String("pkg", "", "package import path (default: resolved with go list)")
+	output := flag.String("o", "schema_descriptions.go", "output file, relative to dir")
+	flag.Parse()
+	if err := run(*dir, *pkgPath, *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(dir, pkgPath, output string) error {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return fmt.Errorf("failed to load package %s: %w", dir, err)
+	}
+	if pkgPath == "" {
+		out, err := exec.Command("go", "list", "-C", dir, "-f", "{{.ImportPath}}", ".").Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return fmt.Errorf("failed to resolve import path of %s: %w", dir, err)
+		}
+		pkgPath = strings.TrimSpace(string(out))
+	}
+	descriptions, err := schema.ParseDescriptions(dir)
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if err = schema.WriteDescriptions(&buffer, pkg.Name, pkgPath, descriptions); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), buffer.Bytes(), 0o644)
+}
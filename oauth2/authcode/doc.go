@@ -0,0 +1,10 @@
+// Package authcode implements the OAuth 2.1 authorization code flow with
+// S256 PKCE as an authorization.ProtectedResourceTokenSource and
+// authorization.IdTokenSource.
+//
+// Given ProtectedResourceMetadata and a scope, a TokenSource discovers the
+// authorization server, optionally registers a client dynamically, sends the
+// user through a pluggable Redirector (a loopback listener by default),
+// exchanges the code with the RFC 8707 resource parameter and caches the
+// resulting tokens per (resource, scope), refreshing them when they expire.
+package authcode
@@ -0,0 +1,55 @@
+package authcode
+
+import (
+	"net/http"
+
+	"github.com/viant/mcp-protocol/oauth2/registration"
+	"github.com/viant/mcp-protocol/oauth2/store"
+)
+
+// Option customizes a TokenSource.
+type Option func(s *TokenSource)
+
+// WithClient sets a pre-registered client identity; clientSecret is empty for public clients.
+func WithClient(clientID, clientSecret string) Option {
+	return func(s *TokenSource) {
+		s.clientID = clientID
+		s.clientSecret = clientSecret
+	}
+}
+
+// WithRegistration enables dynamic client registration when no client identity is configured.
+func WithRegistration(registrar *registration.Client, clientName string) Option {
+	return func(s *TokenSource) {
+		s.registrar = registrar
+		s.clientName = clientName
+	}
+}
+
+// WithRedirector sets the Redirector used to obtain the authorization code.
+func WithRedirector(redirector Redirector) Option {
+	return func(s *TokenSource) {
+		s.redirector = redirector
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for discovery and token requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *TokenSource) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithOpenID requests the "openid" scope so that an id_token is issued for IdToken.
+func WithOpenID() Option {
+	return func(s *TokenSource) {
+		s.openID = true
+	}
+}
+
+// WithStore persists issued tokens so that they survive restarts and are shared between processes.
+func WithStore(tokens store.TokenStore) Option {
+	return func(s *TokenSource) {
+		s.store = tokens
+	}
+}
@@ -0,0 +1,137 @@
+package authcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Redirector sends the user agent to the authorization endpoint and captures the redirect back to the client.
+type Redirector interface {
+	// RedirectURI returns the redirect URI registered for the client.
+	RedirectURI() string
+	// Authorize presents authURL to the user and returns the query parameters of the authorization response.
+	Authorize(ctx context.Context, authURL string) (url.Values, error)
+}
+
+// BrowserFunc opens a URL in the user agent.
+type BrowserFunc func(ctx context.Context, URL string) error
+
+// OpenBrowser opens URL with the platform default browser.
+func OpenBrowser(ctx context.Context, URL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "open", URL)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "rundll32", "url.dll,FileProtocolHandler", URL)
+	default:
+		cmd = exec.CommandContext(ctx, "xdg-open", URL)
+	}
+	return cmd.Start()
+}
+
+// Loopback is a Redirector receiving the authorization response on a loopback HTTP listener (RFC 8252 §7.3).
+type Loopback struct {
+	listener    net.Listener
+	server      *http.Server
+	path        string
+	browser     BrowserFunc
+	flows       *syncmap.Map[string, chan url.Values]
+	redirectURI string
+}
+
+// RedirectURI returns the loopback redirect URI.
+func (l *Loopback) RedirectURI() string {
+	return l.redirectURI
+}
+
+// Authorize opens authURL in the browser and waits for the authorization response carrying its state,
+// so that concurrent authorizations each receive their own redirect.
+func (l *Loopback) Authorize(ctx context.Context, authURL string) (url.Values, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization URL: %w", err)
+	}
+	state := u.Query().Get("state")
+	if state == "" {
+		return nil, errors.New("authorization URL has no state")
+	}
+	responses := make(chan url.Values, 1)
+	l.flows.Put(state, responses)
+	defer l.flows.Delete(state)
+	if err := l.browser(ctx, authURL); err != nil {
+		return nil, fmt.Errorf("failed to open browser: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case values := <-responses:
+		return values, nil
+	}
+}
+
+// Close stops the loopback listener.
+func (l *Loopback) Close() error {
+	return l.server.Close()
+}
+
+func (l *Loopback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != l.path {
+		http.NotFound(w, r)
+		return
+	}
+	query := r.URL.Query()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	responses, ok := l.flows.Get(query.Get("state"))
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("<html><body>Unknown or expired authorization request. You may close this window.</body></html>"))
+		return
+	}
+	select {
+	case responses <- query:
+	default:
+	}
+	if query.Get("error") != "" {
+		_, _ = w.Write([]byte("<html><body>Authorization failed. You may close this window.</body></html>"))
+		return
+	}
+	_, _ = w.Write([]byte("<html><body>Authorization complete. You may close this window.</body></html>"))
+}
+
+// NewLoopback starts a loopback listener on 127.0.0.1:port (0 selects an ephemeral port) serving the redirect at path.
+// When browser is nil, OpenBrowser is used.
+func NewLoopback(port int, path string, browser BrowserFunc) (*Loopback, error) {
+	if path == "" {
+		path = "/callback"
+	}
+	if browser == nil {
+		browser = OpenBrowser
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start loopback listener: %w", err)
+	}
+	ret := &Loopback{
+		listener:    listener,
+		path:        path,
+		browser:     browser,
+		flows:       syncmap.NewMap[string, chan url.Values](),
+		redirectURI: fmt.Sprintf("http://%s%s", listener.Addr().String(), path),
+	}
+	ret.server = &http.Server{Handler: ret}
+	go func() {
+		if err := ret.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = listener.Close()
+		}
+	}()
+	return ret, nil
+}
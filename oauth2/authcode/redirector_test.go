@@ -0,0 +1,60 @@
+package authcode
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopback_Authorize(t *testing.T) {
+	opened := make(chan string, 2)
+	loopback, err := NewLoopback(0, "", func(_ context.Context, URL string) error {
+		opened <- URL
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer loopback.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	responses := map[string]url.Values{}
+	var mux sync.Mutex
+	for _, state := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(state string) {
+			defer wg.Done()
+			values, err := loopback.Authorize(ctx, "https://as.example.com/authorize?state="+state)
+			assert.NoError(t, err, state)
+			mux.Lock()
+			responses[state] = values
+			mux.Unlock()
+		}(state)
+	}
+	<-opened
+	<-opened
+
+	resp, err := http.Get(loopback.RedirectURI() + "?state=other&code=x")
+	if assert.NoError(t, err) {
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+	// redirects arrive in the reverse order of the authorizations; each flow receives its own
+	for _, state := range []string{"b", "a"} {
+		resp, err := http.Get(loopback.RedirectURI() + "?state=" + state + "&code=code-" + state)
+		if assert.NoError(t, err) {
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, "code-a", responses["a"].Get("code"))
+	assert.Equal(t, "code-b", responses["b"].Get("code"))
+}
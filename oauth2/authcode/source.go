@@ -0,0 +1,426 @@
+package authcode
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/oauth2/registration"
+	"github.com/viant/mcp-protocol/oauth2/store"
+	"github.com/viant/mcp-protocol/syncmap"
+	"golang.org/x/oauth2"
+)
+
+var (
+	_ authorization.ProtectedResourceTokenSource = (*TokenSource)(nil)
+	_ authorization.IdTokenSource                = (*TokenSource)(nil)
+)
+
+// TokenSource obtains tokens for protected resources with the authorization code flow and S256 PKCE.
+type TokenSource struct {
+	clientID     string
+	clientSecret string
+	registrar    *registration.Client
+	clientName   string
+	redirector   Redirector
+	httpClient   *http.Client
+	openID       bool
+	store        store.TokenStore
+	servers      *syncmap.Map[string, *meta.AuthorizationServerMetadata]
+	tokens       *syncmap.Map[string, *entry]
+	locks        *syncmap.Locker[string]
+	loopback     *Loopback
+	mux          sync.Mutex
+}
+
+// entry is a cached token together with the authorization server that issued it.
+type entry struct {
+	token  *oauth2.Token
+	issuer string
+	auth   grant.Authenticator
+}
+
+// ProtectedResourceToken returns a cached, refreshed or newly authorized token for the resource and scope.
+// When scope is empty, all scopes advertised by the resource are requested.
+func (s *TokenSource) ProtectedResourceToken(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope string) (*oauth2.Token, error) {
+	if protectedResource == nil {
+		return nil, errors.New("protected resource metadata was nil")
+	}
+	if scope == "" {
+		scope = strings.Join(protectedResource.ScopesSupported, " ")
+	}
+	details, err := authorization.EncodeAuthorizationDetails(authorization.AuthorizationDetailsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey(protectedResource.Resource, scope, details)
+
+	// only requests for the same token wait on an interactive authorization in progress
+	unlock := s.locks.Lock(key)
+	defer unlock()
+	if cached, ok := s.tokens.Get(key); ok {
+		if cached.token.Valid() {
+			return cached.token, nil
+		}
+		if s.store == nil {
+			if token, err := s.refresh(ctx, protectedResource, cached); err == nil {
+				s.tokens.Put(key, &entry{token: token, issuer: cached.issuer, auth: cached.auth})
+				return token, nil
+			}
+		}
+		s.tokens.Delete(key)
+	}
+	if s.store != nil {
+		if cached := s.stored(ctx, protectedResource, scope, details); cached != nil {
+			s.tokens.Put(key, cached)
+			return cached.token, nil
+		}
+	}
+	token, cached, err := s.authorize(ctx, protectedResource, scope, details)
+	if err != nil {
+		return nil, err
+	}
+	cached.token = token
+	s.tokens.Put(key, cached)
+	if s.store != nil {
+		// a failure to persist does not invalidate the freshly issued token
+		_ = s.store.Put(ctx, store.Key{Issuer: cached.issuer, Resource: protectedResource.Resource, Scope: scope, AuthorizationDetails: details}, token)
+	}
+	return token, nil
+}
+
+// stored returns a valid token from the token store, refreshing it under the store lock so that
+// a rotated refresh token is never used twice across processes.
+func (s *TokenSource) stored(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope, details string) *entry {
+	if len(protectedResource.AuthorizationServers) == 0 {
+		return nil
+	}
+	ret := &entry{issuer: protectedResource.AuthorizationServers[0]}
+	key := store.Key{Issuer: ret.issuer, Resource: protectedResource.Resource, Scope: scope, AuthorizationDetails: details}
+	token, err := s.store.Update(ctx, key, func(current *oauth2.Token) (*oauth2.Token, error) {
+		if current == nil || current.Valid() {
+			return current, nil
+		}
+		ret.token = current
+		refreshed, err := s.refresh(ctx, protectedResource, ret)
+		if err != nil {
+			return nil, nil // the stored grant is no longer usable
+		}
+		return refreshed, nil
+	})
+	if err != nil || token == nil {
+		return nil
+	}
+	ret.token = token
+	return ret
+}
+
+// IdToken returns the OIDC id_token issued together with token as an oauth2.Token.
+// When the token carries no id_token, the cached entry for the access token is consulted
+// and refreshed if necessary.
+func (s *TokenSource) IdToken(ctx context.Context, token *oauth2.Token, protectedResource *meta.ProtectedResourceMetadata) (*oauth2.Token, error) {
+	if token == nil {
+		return nil, errors.New("token was nil")
+	}
+	if idToken, ok := token.Extra("id_token").(string); ok && idToken != "" {
+		if ret := newIdToken(idToken); ret.Valid() || token.RefreshToken == "" {
+			return ret, nil
+		}
+	}
+	var found *entry
+	var foundKey string
+	s.tokens.Range(func(key string, value *entry) bool {
+		if value.token.AccessToken == token.AccessToken || (token.RefreshToken != "" && value.token.RefreshToken == token.RefreshToken) {
+			found, foundKey = value, key
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		found = &entry{token: token}
+		if protectedResource != nil && len(protectedResource.AuthorizationServers) > 0 {
+			found.issuer = protectedResource.AuthorizationServers[0]
+		}
+	}
+	if found.token.RefreshToken == "" || found.issuer == "" {
+		return nil, errors.New("token has no id_token; request the openid scope")
+	}
+	lockKey := foundKey
+	if lockKey == "" {
+		lockKey = found.token.RefreshToken
+	}
+	unlock := s.locks.Lock(lockKey)
+	defer unlock()
+	refreshed, err := s.refresh(ctx, protectedResource, found)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh id_token: %w", err)
+	}
+	if foundKey != "" {
+		s.tokens.Put(foundKey, &entry{token: refreshed, issuer: found.issuer, auth: found.auth})
+		if s.store != nil {
+			parts := strings.SplitN(foundKey, "\n", 3)
+			_ = s.store.Put(ctx, store.Key{Issuer: found.issuer, Resource: parts[0], Scope: parts[1], AuthorizationDetails: parts[2]}, refreshed)
+		}
+	}
+	idToken, _ := refreshed.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, errors.New("authorization server did not issue an id_token")
+	}
+	return newIdToken(idToken), nil
+}
+
+// authorize runs the interactive authorization code flow.
+// details is the encoded authorization_details parameter (RFC 9396 §2), empty when not requested.
+func (s *TokenSource) authorize(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope, details string) (*oauth2.Token, *entry, error) {
+	redirector, err := s.redirect()
+	if err != nil {
+		return nil, nil, err
+	}
+	server, err := s.authorizationServer(ctx, protectedResource)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(server.CodeChallengeMethodsSupported, "S256") {
+		return nil, nil, fmt.Errorf("authorization server %s does not support S256 PKCE", server.Issuer)
+	}
+	if server.AuthorizationEndpoint == "" || server.TokenEndpoint == "" {
+		return nil, nil, fmt.Errorf("authorization server %s does not support the authorization code flow", server.Issuer)
+	}
+	clientID, auth, err := s.client(ctx, server)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.openID && !containsScope(scope, "openid") {
+		scope = strings.TrimSpace(scope + " openid")
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	state, err := randomString()
+	if err != nil {
+		return nil, nil, err
+	}
+	redirectURI := redirector.RedirectURI()
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {clientID},
+		"redirect_uri":          {redirectURI},
+		"state":                 {state},
+		"code_challenge":        {oauth2.S256ChallengeFromVerifier(verifier)},
+		"code_challenge_method": {"S256"},
+		"resource":              {protectedResource.Resource},
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	if details != "" {
+		query.Set(authorization.AuthorizationDetailsParameter, details)
+	}
+	authURL, err := withQuery(server.AuthorizationEndpoint, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := redirector.Authorize(ctx, authURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code := response.Get("error"); code != "" {
+		return nil, nil, &grant.Error{Code: code, Description: response.Get("error_description"), URI: response.Get("error_uri")}
+	}
+	if response.Get("state") != state {
+		return nil, nil, errors.New("authorization response state mismatch")
+	}
+	// RFC 9207: reject responses issued by a different authorization server
+	if iss := response.Get("iss"); iss != "" && iss != server.Issuer {
+		return nil, nil, fmt.Errorf("authorization response issuer mismatch: expected %s, got %s", server.Issuer, iss)
+	}
+	code := response.Get("code")
+	if code == "" {
+		return nil, nil, errors.New("authorization response has no code")
+	}
+
+	token, err := grant.Request(ctx, s.httpClient, server.TokenEndpoint, url.Values{
+		"grant_type":    {grant.TypeAuthorizationCode},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+		"resource":      {protectedResource.Resource},
+	}, auth)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return token, &entry{issuer: server.Issuer, auth: auth}, nil
+}
+
+// refresh refreshes a cached token at the issuing authorization server.
+func (s *TokenSource) refresh(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, cached *entry) (*oauth2.Token, error) {
+	if cached.token.RefreshToken == "" {
+		return nil, errors.New("token has no refresh_token")
+	}
+	server, err := s.discover(ctx, cached.issuer)
+	if err != nil {
+		return nil, err
+	}
+	if cached.auth == nil {
+		if _, cached.auth, err = s.client(ctx, server); err != nil {
+			return nil, err
+		}
+	}
+	params := url.Values{}
+	if protectedResource != nil {
+		params.Set("resource", protectedResource.Resource)
+	}
+	return grant.RefreshToken(ctx, s.httpClient, server.TokenEndpoint, cached.token, params, cached.auth)
+}
+
+// authorizationServer selects and discovers the first authorization server of the resource.
+func (s *TokenSource) authorizationServer(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata) (*meta.AuthorizationServerMetadata, error) {
+	if len(protectedResource.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("protected resource %s has no authorization_servers", protectedResource.Resource)
+	}
+	return s.discover(ctx, protectedResource.AuthorizationServers[0])
+}
+
+func (s *TokenSource) discover(ctx context.Context, issuer string) (*meta.AuthorizationServerMetadata, error) {
+	if server, ok := s.servers.Get(issuer); ok {
+		return server, nil
+	}
+	server, err := meta.FetchAuthorizationServerMetadata(ctx, issuer, s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	s.servers.Put(issuer, server)
+	return server, nil
+}
+
+// client returns the client_id and token endpoint authenticator, registering a client when needed.
+func (s *TokenSource) client(ctx context.Context, server *meta.AuthorizationServerMetadata) (string, grant.Authenticator, error) {
+	if s.clientID != "" {
+		auth, err := grant.NewAuthenticator("", s.clientID, s.clientSecret)
+		return s.clientID, auth, err
+	}
+	if s.registrar == nil {
+		return "", nil, errors.New("client_id was not configured and dynamic registration is disabled")
+	}
+	redirector, err := s.redirect()
+	if err != nil {
+		return "", nil, err
+	}
+	metadata := registration.NewClientMetadata(s.clientName, redirector.RedirectURI())
+	metadata.Negotiate(server, registration.AuthMethodNone, registration.AuthMethodClientSecretBasic, registration.AuthMethodClientSecretPost)
+	info, err := s.registrar.Ensure(ctx, server, metadata)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to register client: %w", err)
+	}
+	auth, err := grant.NewAuthenticator(info.TokenEndpointAuthMethod, info.ClientID, info.ClientSecret)
+	return info.ClientID, auth, err
+}
+
+// redirect returns the configured Redirector, starting the default loopback redirector on first use.
+func (s *TokenSource) redirect() (Redirector, error) {
+	if s.redirector != nil {
+		return s.redirector, nil
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.loopback == nil {
+		loopback, err := NewLoopback(0, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		s.loopback = loopback
+	}
+	return s.loopback, nil
+}
+
+// Close stops the default loopback redirector if it was started; a Redirector supplied with
+// WithRedirector is owned and closed by the caller.
+func (s *TokenSource) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.loopback == nil {
+		return nil
+	}
+	err := s.loopback.Close()
+	s.loopback = nil
+	return err
+}
+
+// newIdToken wraps an id_token, taking its expiry from the exp claim.
+func newIdToken(idToken string) *oauth2.Token {
+	ret := &oauth2.Token{AccessToken: idToken, TokenType: "Bearer"}
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return ret
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ret
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+		ret.Expiry = time.Unix(claims.Exp, 0)
+	}
+	return ret
+}
+
+func cacheKey(resource, scope, details string) string {
+	return resource + "\n" + scope + "\n" + details
+}
+
+func withQuery(endpoint string, query url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	values := u.Query()
+	for k, v := range query {
+		values[k] = v
+	}
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func containsScope(scope, candidate string) bool {
+	return slices.Contains(strings.Fields(scope), candidate)
+}
+
+// New creates an authorization code TokenSource. Unless WithRedirector is supplied, a loopback
+// redirector on an ephemeral port with the default browser is started on first authorization
+// and released by Close.
+func New(options ...Option) (*TokenSource, error) {
+	ret := &TokenSource{
+		servers: syncmap.NewMap[string, *meta.AuthorizationServerMetadata](),
+		tokens:  syncmap.NewMap[string, *entry](),
+		locks:   syncmap.NewLocker[string](),
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	return ret, nil
+}
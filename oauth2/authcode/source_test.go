@@ -0,0 +1,162 @@
+package authcode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"golang.org/x/oauth2"
+)
+
+type testRedirector struct {
+	authURL string
+}
+
+func (r *testRedirector) RedirectURI() string {
+	return "http://127.0.0.1/callback"
+}
+
+func (r *testRedirector) Authorize(_ context.Context, authURL string) (url.Values, error) {
+	r.authURL = authURL
+	u, _ := url.Parse(authURL)
+	return url.Values{"code": {"code-1"}, "state": {u.Query().Get("state")}}, nil
+}
+
+// blockingRedirector holds authorization of the "blocked" scope until release is closed.
+type blockingRedirector struct {
+	testRedirector
+	release chan struct{}
+}
+
+func (r *blockingRedirector) Authorize(ctx context.Context, authURL string) (url.Values, error) {
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("scope") == "blocked" {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return url.Values{"code": {"code-1"}, "state": {u.Query().Get("state")}}, nil
+}
+
+func newAuthorizationServer(t *testing.T, onToken func(form url.Values)) *httptest.Server {
+	var issuer string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/oauth-authorization-server":
+			_ = json.NewEncoder(w).Encode(meta.AuthorizationServerMetadata{
+				Issuer:                        issuer,
+				AuthorizationEndpoint:         issuer + "/authorize",
+				TokenEndpoint:                 issuer + "/token",
+				CodeChallengeMethodsSupported: []string{"S256"},
+			})
+		case "/token":
+			_ = r.ParseForm()
+			onToken(r.PostForm)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"at","token_type":"Bearer","expires_in":3600,"refresh_token":"rt","id_token":"a.eyJleHAiOjQxMDI0NDQ4MDB9.c"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	issuer = srv.URL
+	return srv
+}
+
+func TestTokenSource_ProtectedResourceToken(t *testing.T) {
+	var tokenRequests []url.Values
+	srv := newAuthorizationServer(t, func(form url.Values) {
+		tokenRequests = append(tokenRequests, form)
+	})
+	issuer := srv.URL
+
+	redirector := &testRedirector{}
+	source, err := New(WithClient("client-1", ""), WithRedirector(redirector), WithHTTPClient(srv.Client()))
+	assert.NoError(t, err)
+	resource := &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com", AuthorizationServers: []string{issuer}}
+
+	token, err := source.ProtectedResourceToken(context.Background(), resource, "read")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "at", token.AccessToken)
+
+	authURL, _ := url.Parse(redirector.authURL)
+	query := authURL.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, resource.Resource, query.Get("resource"))
+	assert.Equal(t, "read", query.Get("scope"))
+	assert.Equal(t, 1, len(tokenRequests))
+	assert.Equal(t, query.Get("code_challenge"), oauth2.S256ChallengeFromVerifier(tokenRequests[0].Get("code_verifier")))
+	assert.Equal(t, resource.Resource, tokenRequests[0].Get("resource"))
+
+	cached, err := source.ProtectedResourceToken(context.Background(), resource, "read")
+	assert.NoError(t, err)
+	assert.Same(t, token, cached)
+	assert.Equal(t, 1, len(tokenRequests))
+
+	idToken, err := source.IdToken(context.Background(), token, resource)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4102444800), idToken.Expiry.Unix())
+}
+
+func TestTokenSource_ProtectedResourceToken_PerKeyLocking(t *testing.T) {
+	var mux sync.Mutex
+	requests := 0
+	srv := newAuthorizationServer(t, func(url.Values) {
+		mux.Lock()
+		requests++
+		mux.Unlock()
+	})
+	redirector := &blockingRedirector{release: make(chan struct{})}
+	source, err := New(WithClient("client-1", ""), WithRedirector(redirector), WithHTTPClient(srv.Client()))
+	assert.NoError(t, err)
+	resource := &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com", AuthorizationServers: []string{srv.URL}}
+
+	var wg sync.WaitGroup
+	blocked := make([]*oauth2.Token, 2)
+	for i := range blocked {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			blocked[i], _ = source.ProtectedResourceToken(context.Background(), resource, "blocked")
+		}(i)
+	}
+
+	// a pending interactive authorization must not hold up tokens for other keys
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	token, err := source.ProtectedResourceToken(ctx, resource, "read")
+	assert.NoError(t, err)
+	assert.Equal(t, "at", token.AccessToken)
+
+	close(redirector.release)
+	wg.Wait()
+	assert.NotNil(t, blocked[0])
+	assert.Same(t, blocked[0], blocked[1])
+	assert.Equal(t, 2, requests) // one for "read", one shared by both "blocked" callers
+}
+
+func TestTokenSource_Close(t *testing.T) {
+	source, err := New(WithClient("client-1", ""))
+	assert.NoError(t, err)
+	assert.Nil(t, source.loopback)
+	assert.NoError(t, source.Close())
+
+	redirector, err := source.redirect()
+	assert.NoError(t, err)
+	assert.Same(t, source.loopback, redirector)
+	assert.NoError(t, source.Close())
+	assert.Nil(t, source.loopback)
+	_, err = http.Get(redirector.RedirectURI())
+	assert.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package challenge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Bearer token error codes (RFC 6750 §3.1).
+const (
+	ErrorInvalidRequest    = "invalid_request"
+	ErrorInvalidToken      = "invalid_token"
+	ErrorInsufficientScope = "insufficient_scope"
+)
+
+// Challenge represents a single authentication challenge of a WWW-Authenticate header.
+type Challenge struct {
+	Scheme string
+	// Token68 holds the token68 form of the challenge credentials, if used instead of parameters.
+	Token68 string
+	// Params holds auth-params keyed by lower-case name.
+	Params map[string]string
+}
+
+// Param returns the named parameter value.
+func (c *Challenge) Param(name string) string {
+	return c.Params[strings.ToLower(name)]
+}
+
+// Realm returns the realm parameter.
+func (c *Challenge) Realm() string {
+	return c.Param("realm")
+}
+
+// ResourceMetadata returns the RFC 9728 resource_metadata parameter.
+func (c *Challenge) ResourceMetadata() string {
+	return c.Param("resource_metadata")
+}
+
+// Scope returns the scope parameter.
+func (c *Challenge) Scope() string {
+	return c.Param("scope")
+}
+
+// ErrorCode returns the error parameter.
+func (c *Challenge) ErrorCode() string {
+	return c.Param("error")
+}
+
+// ErrorDescription returns the error_description parameter.
+func (c *Challenge) ErrorDescription() string {
+	return c.Param("error_description")
+}
+
+// String formats the challenge for a WWW-Authenticate header. Well-known parameters are
+// written first, the rest in lexical order.
+func (c *Challenge) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(c.Scheme)
+	if c.Token68 != "" {
+		builder.WriteString(" ")
+		builder.WriteString(c.Token68)
+		return builder.String()
+	}
+	var names []string
+	for name := range c.Params {
+		names = append(names, name)
+	}
+	order := map[string]int{"realm": 1, "resource_metadata": 2, "scope": 3, "error": 4, "error_description": 5, "error_uri": 6}
+	sort.Slice(names, func(i, j int) bool {
+		oi, oj := order[names[i]], order[names[j]]
+		if oi == 0 {
+			oi = len(order) + 1
+		}
+		if oj == 0 {
+			oj = len(order) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+	for i, name := range names {
+		if i == 0 {
+			builder.WriteString(" ")
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(name)
+		builder.WriteString("=")
+		builder.WriteString(quote(c.Params[name]))
+	}
+	return builder.String()
+}
+
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// Find returns the first challenge with the given scheme (case-insensitive).
+func Find(challenges []*Challenge, scheme string) *Challenge {
+	for _, candidate := range challenges {
+		if strings.EqualFold(candidate.Scheme, scheme) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// Parse parses the values of one or more WWW-Authenticate headers (RFC 9110 §11.6.1).
+func Parse(headers ...string) ([]*Challenge, error) {
+	var result []*Challenge
+	for _, header := range headers {
+		challenges, err := parse(header)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, challenges...)
+	}
+	return result, nil
+}
+
+func parse(header string) ([]*Challenge, error) {
+	p := &parser{input: header}
+	var result []*Challenge
+	var current *Challenge
+	for {
+		p.skip(" \t,")
+		if p.done() {
+			return result, nil
+		}
+		name := p.token()
+		if name == "" {
+			return nil, fmt.Errorf("invalid WWW-Authenticate header at %d: %q", p.pos, header)
+		}
+		p.skip(" \t")
+		if current != nil && p.peek() == '=' {
+			p.pos++
+			p.skip(" \t")
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			current.Params[strings.ToLower(name)] = value
+			continue
+		}
+		// new challenge: name is the auth-scheme
+		current = &Challenge{Scheme: name, Params: map[string]string{}}
+		result = append(result, current)
+		p.skip(" \t")
+		if p.done() || p.peek() == ',' {
+			continue
+		}
+		// token68 credentials: token followed only by '=' padding, then end or comma
+		mark := p.pos
+		candidate := p.token68()
+		p.skip(" \t")
+		if candidate != "" && (p.done() || p.peek() == ',') {
+			current.Token68 = candidate
+			continue
+		}
+		p.pos = mark
+	}
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skip(chars string) {
+	for !p.done() && strings.IndexByte(chars, p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) token() string {
+	start := p.pos
+	for !p.done() && isTokenChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) token68() string {
+	start := p.pos
+	for !p.done() && (isAlphaNum(p.input[p.pos]) || strings.IndexByte("-._~+/", p.input[p.pos]) >= 0) {
+		p.pos++
+	}
+	for !p.done() && p.input[p.pos] == '=' {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) value() (string, error) {
+	if p.peek() != '"' {
+		return p.token(), nil
+	}
+	p.pos++
+	builder := strings.Builder{}
+	for !p.done() {
+		c := p.input[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if p.done() {
+				return "", fmt.Errorf("unterminated quoted-string in %q", p.input)
+			}
+			builder.WriteByte(p.input[p.pos])
+			p.pos++
+		case '"':
+			return builder.String(), nil
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted-string in %q", p.input)
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isTokenChar(c byte) bool {
+	return isAlphaNum(c) || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
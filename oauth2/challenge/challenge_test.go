@@ -0,0 +1,65 @@
+package challenge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected []*Challenge
+	}{
+		{
+			name:   "bearer with params",
+			header: `Bearer realm="mcp", resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource", scope="files:read files:write", error="insufficient_scope"`,
+			expected: []*Challenge{{Scheme: "Bearer", Params: map[string]string{
+				"realm":             "mcp",
+				"resource_metadata": "https://mcp.example.com/.well-known/oauth-protected-resource",
+				"scope":             "files:read files:write",
+				"error":             "insufficient_scope",
+			}}},
+		},
+		{
+			name:   "multiple challenges",
+			header: `Basic abc==, Bearer error=invalid_token, DPoP algs="ES256 RS256"`,
+			expected: []*Challenge{
+				{Scheme: "Basic", Token68: "abc==", Params: map[string]string{}},
+				{Scheme: "Bearer", Params: map[string]string{"error": "invalid_token"}},
+				{Scheme: "DPoP", Params: map[string]string{"algs": "ES256 RS256"}},
+			},
+		},
+		{
+			name:     "escaped quote",
+			header:   `Bearer realm="a\"b"`,
+			expected: []*Challenge{{Scheme: "Bearer", Params: map[string]string{"realm": `a"b`}}},
+		},
+		{
+			name:     "bare scheme",
+			header:   `Bearer`,
+			expected: []*Challenge{{Scheme: "Bearer", Params: map[string]string{}}},
+		},
+	}
+	for _, tc := range testCases {
+		actual, err := Parse(tc.header)
+		assert.NoError(t, err, tc.name)
+		assert.EqualValues(t, tc.expected, actual, tc.name)
+	}
+}
+
+func TestFromAuthorization(t *testing.T) {
+	auth := &authorization.Authorization{
+		RequiredScopes:            []string{"files:read"},
+		ProtectedResourceMetadata: &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com/mcp"},
+	}
+	actual := FromAuthorization(auth, ErrorInsufficientScope, "files:read required").String()
+	assert.Equal(t, `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp", scope="files:read", error="insufficient_scope", error_description="files:read required"`, actual)
+
+	parsed, err := Parse(actual)
+	assert.NoError(t, err)
+	assert.Equal(t, "files:read", parsed[0].Scope())
+}
@@ -0,0 +1,251 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/syncmap"
+	"golang.org/x/oauth2"
+)
+
+// ErrResourceMismatch is returned when protected resource metadata describes another resource than the requested one.
+var ErrResourceMismatch = errors.New("protected resource metadata does not match the requested resource")
+
+// ServerSelector picks the authorization server to use from the resource's authorization_servers.
+type ServerSelector func(ctx context.Context, resource *meta.ProtectedResourceMetadata) (string, error)
+
+// Discovery drives the client side of the MCP authorization flow from 401/403 challenges.
+type Discovery struct {
+	tokenSource authorization.ProtectedResourceTokenSource
+	httpClient  *http.Client
+	selector    ServerSelector
+	resources   *syncmap.Map[string, *resourceState] // keyed by resourceKey of the resource identifier
+}
+
+// resourceState tracks the metadata and the scopes requested so far for a protected resource.
+type resourceState struct {
+	metadata *meta.ProtectedResourceMetadata
+	scopes   []string
+	mux      sync.Mutex
+}
+
+// Token returns a token for requestURL after a challenge has been handled for its resource,
+// or nil when the resource is not known yet.
+func (d *Discovery) Token(ctx context.Context, requestURL string) (*oauth2.Token, error) {
+	state := d.lookup(requestURL)
+	if state == nil {
+		return nil, nil
+	}
+	state.mux.Lock()
+	defer state.mux.Unlock()
+	return d.tokenSource.ProtectedResourceToken(ctx, state.metadata, strings.Join(state.scopes, " "))
+}
+
+// HandleResponse handles a 401 or 403 response to requestURL and returns a token to retry with.
+// It returns nil when the response carries no actionable Bearer challenge.
+func (d *Discovery) HandleResponse(ctx context.Context, requestURL string, response *http.Response) (*oauth2.Token, error) {
+	if response.StatusCode != http.StatusUnauthorized && response.StatusCode != http.StatusForbidden {
+		return nil, nil
+	}
+	challenges, err := Parse(response.Header.Values("WWW-Authenticate")...)
+	if err != nil {
+		return nil, err
+	}
+	bearer := Find(challenges, SchemeBearer)
+	if bearer == nil {
+		if response.StatusCode == http.StatusForbidden {
+			return nil, nil
+		}
+		bearer = &Challenge{Scheme: SchemeBearer, Params: map[string]string{}}
+	}
+	if response.StatusCode == http.StatusForbidden && bearer.ErrorCode() != ErrorInsufficientScope {
+		return nil, nil
+	}
+	return d.HandleChallenge(ctx, requestURL, bearer)
+}
+
+// HandleChallenge resolves the protected resource metadata referenced by the challenge and obtains a token.
+// For insufficient_scope the scopes from the challenge are added to the previously requested ones (step-up).
+func (d *Discovery) HandleChallenge(ctx context.Context, requestURL string, challenge *Challenge) (*oauth2.Token, error) {
+	state := d.lookup(requestURL)
+	if state == nil || challenge.ResourceMetadata() != "" {
+		metadata, err := d.fetchMetadata(ctx, requestURL, challenge.ResourceMetadata())
+		if err != nil {
+			return nil, err
+		}
+		key := resourceKey(metadata.Resource)
+		if existing, ok := d.resources.Get(key); ok {
+			state = existing
+			state.mux.Lock()
+			state.metadata = metadata
+			state.mux.Unlock()
+		} else {
+			state = &resourceState{metadata: metadata}
+			d.resources.Put(key, state)
+		}
+	}
+
+	state.mux.Lock()
+	defer state.mux.Unlock()
+	scope := strings.Fields(challenge.Scope())
+	if challenge.ErrorCode() == ErrorInsufficientScope {
+		if len(scope) == 0 {
+			return nil, fmt.Errorf("insufficient_scope challenge for %s lists no scope", requestURL)
+		}
+		for _, candidate := range scope {
+			if !slices.Contains(state.scopes, candidate) {
+				state.scopes = append(state.scopes, candidate)
+			}
+		}
+	} else if len(scope) > 0 {
+		state.scopes = scope
+	}
+
+	metadata, err := d.selectServer(ctx, state.metadata)
+	if err != nil {
+		return nil, err
+	}
+	token, err := d.tokenSource.ProtectedResourceToken(ctx, metadata, strings.Join(state.scopes, " "))
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain token for %s: %w", metadata.Resource, err)
+	}
+	return token, nil
+}
+
+// fetchMetadata fetches the resource metadata from the challenge URL or, when absent, from the
+// well-known locations derived from requestURL (path-suffixed first, then the root).
+// Metadata describing another resource than requestURL is rejected (RFC 9728 §3.3, §7.3).
+func (d *Discovery) fetchMetadata(ctx context.Context, requestURL, metadataURL string) (*meta.ProtectedResourceMetadata, error) {
+	if metadataURL != "" {
+		metadata, err := meta.FetchProtectedResourceMetadata(ctx, metadataURL, d.httpClient)
+		if err != nil {
+			return nil, err
+		}
+		if err = validateResource(requestURL, metadata.Resource); err != nil {
+			return nil, err
+		}
+		return metadata, nil
+	}
+	candidates, err := wellKnownCandidates(requestURL)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, candidate := range candidates {
+		metadata, err := meta.FetchProtectedResourceMetadata(ctx, candidate, d.httpClient)
+		if err == nil {
+			err = validateResource(requestURL, metadata.Resource)
+		}
+		if err == nil {
+			return metadata, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("failed to discover protected resource metadata for %s: %w", requestURL, errors.Join(errs...))
+}
+
+// selectServer returns metadata whose first authorization server is the selected one.
+func (d *Discovery) selectServer(ctx context.Context, metadata *meta.ProtectedResourceMetadata) (*meta.ProtectedResourceMetadata, error) {
+	if d.selector == nil || len(metadata.AuthorizationServers) < 2 {
+		return metadata, nil
+	}
+	selected, err := d.selector(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+	servers := []string{selected}
+	for _, server := range metadata.AuthorizationServers {
+		if server != selected {
+			servers = append(servers, server)
+		}
+	}
+	ret := *metadata
+	ret.AuthorizationServers = servers
+	return &ret, nil
+}
+
+// validateResource checks that the resource identifier covers requestURL: same origin and a path
+// equal to, or a path prefix of, the requested path.
+func validateResource(requestURL, resource string) error {
+	requested, err := url.Parse(requestURL)
+	if err != nil {
+		return fmt.Errorf("invalid request URL: %w", err)
+	}
+	identifier, err := url.Parse(resource)
+	if err != nil || resource == "" {
+		return fmt.Errorf("invalid protected resource %q: %w", resource, ErrResourceMismatch)
+	}
+	if !strings.EqualFold(identifier.Scheme, requested.Scheme) || !strings.EqualFold(identifier.Host, requested.Host) {
+		return fmt.Errorf("protected resource %s does not match %s: %w", resource, requestURL, ErrResourceMismatch)
+	}
+	prefix := strings.TrimSuffix(identifier.Path, "/")
+	if prefix != "" && requested.Path != prefix && !strings.HasPrefix(requested.Path, prefix+"/") {
+		return fmt.Errorf("protected resource %s does not match %s: %w", resource, requestURL, ErrResourceMismatch)
+	}
+	return nil
+}
+
+func wellKnownCandidates(requestURL string) ([]string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request URL: %w", err)
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	var result []string
+	if strings.Trim(u.Path, "/") != "" {
+		suffixed, err := meta.ProtectedResourceMetadataURL(u.String())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, suffixed)
+	}
+	u.Path = meta.ProtectedResourceMetadataSuffix
+	u.RawPath = ""
+	return append(result, u.String()), nil
+}
+
+// lookup returns the state of the most specific known resource covering requestURL, or nil.
+func (d *Discovery) lookup(requestURL string) *resourceState {
+	var ret *resourceState
+	matched := ""
+	d.resources.Range(func(key string, state *resourceState) bool {
+		if len(key) > len(matched) && validateResource(requestURL, key) == nil {
+			ret, matched = state, key
+		}
+		return true
+	})
+	return ret
+}
+
+// resourceKey normalizes a validated resource identifier to its origin and path prefix.
+func resourceKey(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return resource
+	}
+	return strings.ToLower(u.Scheme+"://"+u.Host) + strings.TrimSuffix(u.Path, "/")
+}
+
+// NewDiscovery creates a Discovery obtaining tokens from tokenSource.
+func NewDiscovery(tokenSource authorization.ProtectedResourceTokenSource, options ...Option) *Discovery {
+	ret := &Discovery{
+		tokenSource: tokenSource,
+		resources:   syncmap.NewMap[string, *resourceState](),
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	return ret
+}
@@ -0,0 +1,100 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"golang.org/x/oauth2"
+)
+
+type recordingTokenSource struct {
+	resources []string
+}
+
+func (r *recordingTokenSource) ProtectedResourceToken(_ context.Context, protectedResource *meta.ProtectedResourceMetadata, _ string) (*oauth2.Token, error) {
+	r.resources = append(r.resources, protectedResource.Resource)
+	return &oauth2.Token{AccessToken: "at"}, nil
+}
+
+func TestDiscovery_HandleChallenge(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := serverURL + "/mcp"
+		if r.URL.Path == "/other/metadata" {
+			resource = "https://victim.example.com/mcp"
+		}
+		_ = json.NewEncoder(w).Encode(&meta.ProtectedResourceMetadata{Resource: resource, AuthorizationServers: []string{"https://as.example.com"}})
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	testCases := []struct {
+		name        string
+		requestURL  string
+		metadataURL string
+		expectErr   bool
+	}{
+		{name: "well-known metadata", requestURL: serverURL + "/mcp"},
+		{name: "resource path prefix", requestURL: serverURL + "/mcp/tools", metadataURL: serverURL + "/metadata"},
+		{name: "metadata of another resource", requestURL: serverURL + "/mcp", metadataURL: serverURL + "/other/metadata", expectErr: true},
+		{name: "resource path not covering request", requestURL: serverURL + "/api", metadataURL: serverURL + "/metadata", expectErr: true},
+	}
+	for _, tc := range testCases {
+		tokenSource := &recordingTokenSource{}
+		discovery := NewDiscovery(tokenSource)
+		challenge := &Challenge{Scheme: SchemeBearer, Params: map[string]string{}}
+		if tc.metadataURL != "" {
+			challenge.Params["resource_metadata"] = tc.metadataURL
+		}
+		token, err := discovery.HandleChallenge(context.Background(), tc.requestURL, challenge)
+		if tc.expectErr {
+			assert.ErrorIs(t, err, ErrResourceMismatch, tc.name)
+			assert.Empty(t, tokenSource.resources, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.NotNil(t, token, tc.name)
+		assert.Equal(t, []string{serverURL + "/mcp"}, tokenSource.resources, tc.name)
+	}
+}
+
+func TestDiscovery_Token(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /a/metadata describes resource /a, /b/metadata describes /b
+		resource := serverURL + path.Dir(r.URL.Path)
+		_ = json.NewEncoder(w).Encode(&meta.ProtectedResourceMetadata{Resource: resource, AuthorizationServers: []string{"https://as.example.com"}})
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	tokenSource := &recordingTokenSource{}
+	discovery := NewDiscovery(tokenSource)
+	ctx := context.Background()
+	token, err := discovery.Token(ctx, serverURL+"/a/mcp")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	for _, resource := range []string{"/a", "/b"} {
+		challenge := &Challenge{Scheme: SchemeBearer, Params: map[string]string{"resource_metadata": serverURL + resource + "/metadata"}}
+		_, err = discovery.HandleChallenge(ctx, serverURL+resource+"/mcp", challenge)
+		assert.NoError(t, err, resource)
+	}
+	// resources sharing an origin keep their own metadata
+	tokenSource.resources = nil
+	for _, resource := range []string{"/b", "/a"} {
+		_, err = discovery.Token(ctx, serverURL+resource+"/mcp")
+		assert.NoError(t, err, resource)
+	}
+	assert.Equal(t, []string{serverURL + "/b", serverURL + "/a"}, tokenSource.resources)
+
+	token, err = discovery.Token(ctx, serverURL+"/c/mcp")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
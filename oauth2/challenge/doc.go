@@ -0,0 +1,14 @@
+// Package challenge handles OAuth 2.0 bearer token challenges carried by the
+// WWW-Authenticate header (RFC 6750 §3, RFC 9728 §5).
+//
+// On the client side, Discovery performs the chain required by the MCP
+// authorization specification when a server answers 401: it parses the
+// challenge (resource_metadata, scope, error), fetches the Protected Resource
+// Metadata, selects an authorization server and obtains a token from an
+// authorization.ProtectedResourceTokenSource, re-authorizing with additional
+// scopes when the server reports insufficient_scope. Transport wraps this
+// into an http.RoundTripper.
+//
+// On the server side, FromAuthorization formats the challenge for an
+// authorization.Authorization.
+package challenge
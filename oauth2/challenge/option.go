@@ -0,0 +1,20 @@
+package challenge
+
+import "net/http"
+
+// Option customizes a Discovery.
+type Option func(d *Discovery)
+
+// WithHTTPClient sets the HTTP client used to fetch protected resource metadata.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(d *Discovery) {
+		d.httpClient = httpClient
+	}
+}
+
+// WithServerSelector sets the strategy for choosing among multiple authorization servers; by default the first one is used.
+func WithServerSelector(selector ServerSelector) Option {
+	return func(d *Discovery) {
+		d.selector = selector
+	}
+}
@@ -0,0 +1,59 @@
+package challenge
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// SchemeBearer is the bearer token authentication scheme (RFC 6750).
+const SchemeBearer = "Bearer"
+
+// FromAuthorization builds the Bearer challenge a server returns for a request that does not satisfy auth.
+// The resource_metadata parameter points at the well-known metadata URL of the protected resource,
+// and scope lists the scopes required by auth. errorCode is optional (e.g. ErrorInvalidToken,
+// ErrorInsufficientScope) and description is only used together with it.
+func FromAuthorization(auth *authorization.Authorization, errorCode, description string) *Challenge {
+	ret := &Challenge{Scheme: SchemeBearer, Params: map[string]string{}}
+	if auth == nil {
+		return ret
+	}
+	if resource := auth.ProtectedResourceMetadata; resource != nil && resource.Resource != "" {
+		if metadataURL, err := meta.ProtectedResourceMetadataURL(resource.Resource); err == nil {
+			ret.Params["resource_metadata"] = metadataURL
+		}
+	}
+	scopes := auth.RequiredScopes
+	if len(scopes) == 0 && auth.ProtectedResourceMetadata != nil && errorCode != ErrorInsufficientScope {
+		scopes = auth.ProtectedResourceMetadata.ScopesSupported
+	}
+	if len(scopes) > 0 {
+		ret.Params["scope"] = strings.Join(scopes, " ")
+	}
+	if errorCode != "" {
+		ret.Params["error"] = errorCode
+		if description != "" {
+			ret.Params["error_description"] = description
+		}
+	}
+	return ret
+}
+
+// StatusCode returns the HTTP status that accompanies a challenge with the given error code (RFC 6750 §3.1).
+func StatusCode(errorCode string) int {
+	switch errorCode {
+	case ErrorInvalidRequest:
+		return http.StatusBadRequest
+	case ErrorInsufficientScope:
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// Write sets the WWW-Authenticate header for the challenge and writes the matching status code.
+func Write(w http.ResponseWriter, challenge *Challenge) {
+	w.Header().Add("WWW-Authenticate", challenge.String())
+	w.WriteHeader(StatusCode(challenge.ErrorCode()))
+}
@@ -0,0 +1,68 @@
+package challenge
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// Transport is an http.RoundTripper that authorizes requests with tokens obtained by Discovery,
+// retrying once after a 401 or insufficient_scope 403 challenge.
+type Transport struct {
+	Discovery *Discovery
+	// Base is the underlying RoundTripper; http.DefaultTransport when nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	requestURL := req.URL.String()
+	token, err := t.Discovery.Token(ctx, requestURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base().RoundTrip(authorize(req, token))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return resp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // body cannot be replayed
+	}
+	retryToken, err := t.Discovery.HandleResponse(ctx, requestURL, resp)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if retryToken == nil || (token != nil && retryToken.AccessToken == token.AccessToken) {
+		return resp, nil
+	}
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_ = resp.Body.Close()
+	return t.base().RoundTrip(authorize(retry, retryToken))
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// authorize returns a copy of req carrying the token, as required by the http.RoundTripper contract.
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	if token == nil {
+		return req
+	}
+	ret := req.Clone(req.Context())
+	token.SetAuthHeader(ret)
+	return ret
+}
@@ -0,0 +1,9 @@
+// Package clientcredentials implements the OAuth 2.0 client credentials grant
+// (RFC 6749 §4.4) as an authorization.ProtectedResourceTokenSource for
+// machine-to-machine MCP clients such as batch agents.
+//
+// Client authentication is negotiated against the authorization server's
+// token_endpoint_auth_methods_supported and may use client_secret_basic,
+// client_secret_post, private_key_jwt (RFC 7523) or tls_client_auth
+// (RFC 8705).
+package clientcredentials
@@ -0,0 +1,34 @@
+package clientcredentials
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/viant/mcp-protocol/oauth2/store"
+)
+
+// Option customizes a TokenSource.
+type Option func(s *TokenSource)
+
+// WithHTTPClient sets the HTTP client used for discovery and token requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *TokenSource) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithClientCertificate enables tls_client_auth with certificate, presented by the HTTP client
+// set with WithHTTPClient or by a new one.
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return func(s *TokenSource) {
+		s.certificate = &certificate
+		s.credentials.TLSClientCertificate = true
+	}
+}
+
+// WithStore persists issued tokens so that they survive restarts and are shared between processes.
+func WithStore(tokens store.TokenStore) Option {
+	return func(s *TokenSource) {
+		s.store = tokens
+	}
+}
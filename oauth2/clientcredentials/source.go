@@ -0,0 +1,185 @@
+package clientcredentials
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/oauth2/mtls"
+	"github.com/viant/mcp-protocol/oauth2/store"
+	"github.com/viant/mcp-protocol/syncmap"
+	"golang.org/x/oauth2"
+)
+
+var _ authorization.ProtectedResourceTokenSource = (*TokenSource)(nil)
+
+// TokenSource obtains tokens for protected resources with the client credentials grant.
+type TokenSource struct {
+	credentials *grant.Credentials
+	httpClient  *http.Client
+	store       store.TokenStore
+	servers     *syncmap.Map[string, *server]
+	tokens      *syncmap.Map[string, *oauth2.Token]
+	locks       *syncmap.Locker[string]
+	certificate *tls.Certificate
+}
+
+// server holds discovered metadata with the negotiated client authentication.
+type server struct {
+	metadata      *meta.AuthorizationServerMetadata
+	tokenEndpoint string
+	auth          grant.Authenticator
+	method        string
+}
+
+// ProtectedResourceToken returns a cached or newly issued token for the resource and scope.
+// When scope is empty, all scopes advertised by the resource are requested.
+func (s *TokenSource) ProtectedResourceToken(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope string) (*oauth2.Token, error) {
+	if protectedResource == nil {
+		return nil, errors.New("protected resource metadata was nil")
+	}
+	if len(protectedResource.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("protected resource %s has no authorization_servers", protectedResource.Resource)
+	}
+	if scope == "" {
+		scope = strings.Join(protectedResource.ScopesSupported, " ")
+	}
+	details, err := authorization.EncodeAuthorizationDetails(authorization.AuthorizationDetailsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	key := protectedResource.Resource + "\n" + scope + "\n" + details
+
+	unlock := s.locks.Lock(key)
+	defer unlock()
+	if cached, ok := s.tokens.Get(key); ok && cached.Valid() {
+		return cached, nil
+	}
+	storeKey := store.Key{Issuer: protectedResource.AuthorizationServers[0], Resource: protectedResource.Resource, Scope: scope, AuthorizationDetails: details}
+	if s.store != nil {
+		if stored, err := s.store.Get(ctx, storeKey); err == nil && stored != nil && stored.Valid() {
+			s.tokens.Put(key, stored)
+			return stored, nil
+		}
+	}
+	srv, err := s.server(ctx, protectedResource.AuthorizationServers[0])
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{
+		"grant_type": {grant.TypeClientCredentials},
+		"resource":   {protectedResource.Resource},
+	}
+	if scope != "" {
+		params.Set("scope", scope)
+	}
+	if details != "" {
+		params.Set(authorization.AuthorizationDetailsParameter, details)
+	}
+	token, err := grant.Request(ctx, s.httpClient, srv.tokenEndpoint, params, srv.auth)
+	if err != nil {
+		return nil, fmt.Errorf("client credentials grant failed: %w", err)
+	}
+	s.tokens.Put(key, token)
+	if s.store != nil {
+		// a failure to persist does not invalidate the freshly issued token
+		_ = s.store.Put(ctx, storeKey, token)
+	}
+	return token, nil
+}
+
+// AuthMethod returns the authentication method negotiated with the issuer, if already discovered.
+func (s *TokenSource) AuthMethod(issuer string) string {
+	if srv, ok := s.servers.Get(issuer); ok {
+		return srv.method
+	}
+	return ""
+}
+
+func (s *TokenSource) server(ctx context.Context, issuer string) (*server, error) {
+	if srv, ok := s.servers.Get(issuer); ok {
+		return srv, nil
+	}
+	metadata, err := meta.FetchAuthorizationServerMetadata(ctx, issuer, s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.GrantTypesSupported) > 0 && !slices.Contains(metadata.GrantTypesSupported, grant.TypeClientCredentials) {
+		return nil, fmt.Errorf("authorization server %s does not support the client_credentials grant", issuer)
+	}
+	auth, method, err := grant.Negotiate(metadata, s.credentials)
+	if err != nil {
+		return nil, fmt.Errorf("authorization server %s: %w", issuer, err)
+	}
+	srv := &server{metadata: metadata, tokenEndpoint: metadata.TokenEndpoint, auth: auth, method: method}
+	if method == grant.AuthMethodTLSClientAuth {
+		// RFC 8705 §5: mutual-TLS requests go to mtls_endpoint_aliases when advertised
+		if aliases, ok := metadata.Extra["mtls_endpoint_aliases"].(map[string]any); ok {
+			if endpoint, _ := aliases["token_endpoint"].(string); endpoint != "" {
+				srv.tokenEndpoint = endpoint
+			}
+		}
+	}
+	s.servers.Put(issuer, srv)
+	return srv, nil
+}
+
+// New creates a client credentials TokenSource.
+func New(credentials *grant.Credentials, options ...Option) (*TokenSource, error) {
+	if credentials == nil || credentials.ClientID == "" {
+		return nil, errors.New("client_id is required")
+	}
+	copied := *credentials
+	ret := &TokenSource{
+		credentials: &copied,
+		servers:     syncmap.NewMap[string, *server](),
+		tokens:      syncmap.NewMap[string, *oauth2.Token](),
+		locks:       syncmap.NewLocker[string](),
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.certificate != nil {
+		httpClient, err := withCertificate(ret.httpClient, *ret.certificate)
+		if err != nil {
+			return nil, err
+		}
+		ret.httpClient = httpClient
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	return ret, nil
+}
+
+// withCertificate returns a copy of httpClient presenting certificate on TLS connections.
+func withCertificate(httpClient *http.Client, certificate tls.Certificate) (*http.Client, error) {
+	if httpClient == nil {
+		return mtls.NewHTTPClient(certificate, nil), nil
+	}
+	var transport *http.Transport
+	switch actual := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = actual.Clone()
+	default:
+		return nil, fmt.Errorf("client certificate requires an *http.Transport, but HTTP client uses %T", actual)
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	// the cloned config shares the certificates of httpClient, so clip before appending
+	transport.TLSClientConfig.Certificates = append(slices.Clip(transport.TLSClientConfig.Certificates), certificate)
+	ret := *httpClient
+	ret.Transport = transport
+	return &ret, nil
+}
@@ -0,0 +1,38 @@
+package clientcredentials
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+)
+
+func TestNew_ClientCertificate(t *testing.T) {
+	certificate := tls.Certificate{Certificate: [][]byte{{1}}}
+	httpClient := &http.Client{Timeout: time.Second}
+	testCases := []struct {
+		description string
+		options     []Option
+		timeout     time.Duration
+	}{
+		{description: "certificate only", options: []Option{WithClientCertificate(certificate)}},
+		{description: "certificate before HTTP client", options: []Option{WithClientCertificate(certificate), WithHTTPClient(httpClient)}, timeout: time.Second},
+		{description: "certificate after HTTP client", options: []Option{WithHTTPClient(httpClient), WithClientCertificate(certificate)}, timeout: time.Second},
+	}
+	for _, testCase := range testCases {
+		source, err := New(&grant.Credentials{ClientID: "client-1"}, testCase.options...)
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.True(t, source.credentials.TLSClientCertificate, testCase.description)
+		assert.Equal(t, testCase.timeout, source.httpClient.Timeout, testCase.description)
+		transport, ok := source.httpClient.Transport.(*http.Transport)
+		if assert.True(t, ok, testCase.description) {
+			assert.Equal(t, []tls.Certificate{certificate}, transport.TLSClientConfig.Certificates, testCase.description)
+		}
+	}
+	assert.Nil(t, httpClient.Transport)
+}
@@ -0,0 +1,99 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/grant"
+)
+
+// GrantType is the device code grant type (RFC 8628 §3.4).
+const GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Error codes returned while polling the token endpoint (RFC 8628 §3.5).
+const (
+	ErrorAuthorizationPending = "authorization_pending"
+	ErrorSlowDown             = "slow_down"
+	ErrorAccessDenied         = "access_denied"
+	ErrorExpiredToken         = "expired_token"
+)
+
+// Authorization represents the device authorization response (RFC 8628 §3.2).
+type Authorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+	// Expiry is computed from ExpiresIn when the response is received.
+	Expiry time.Time `json:"-"`
+}
+
+// PromptFunc presents the verification URI and user code to the user.
+type PromptFunc func(ctx context.Context, authorization *Authorization) error
+
+// Prompt writes verification instructions to standard error.
+func Prompt(_ context.Context, authorization *Authorization) error {
+	if authorization.VerificationURIComplete != "" {
+		_, err := fmt.Fprintf(os.Stderr, "To authorize, visit %s\nand confirm code: %s\n", authorization.VerificationURIComplete, authorization.UserCode)
+		return err
+	}
+	_, err := fmt.Fprintf(os.Stderr, "To authorize, visit %s\nand enter code: %s\n", authorization.VerificationURI, authorization.UserCode)
+	return err
+}
+
+// requestAuthorization requests device and user codes from the device authorization endpoint (RFC 8628 §3.1).
+func requestAuthorization(ctx context.Context, client *http.Client, endpoint string, params url.Values, auth grant.Authenticator) (*Authorization, error) {
+	header := http.Header{}
+	if auth != nil {
+		if err := auth.Authenticate(ctx, endpoint, header, params); err != nil {
+			return nil, fmt.Errorf("failed to authenticate client: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device authorization response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		ret := &grant.Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, ret) != nil || ret.Code == "" {
+			ret.Code = http.StatusText(resp.StatusCode)
+			ret.Description = string(body)
+		}
+		return nil, ret
+	}
+	ret := &Authorization{}
+	if err := json.Unmarshal(body, ret); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization response: %w", err)
+	}
+	if ret.DeviceCode == "" || ret.UserCode == "" || ret.VerificationURI == "" {
+		return nil, errors.New("device authorization response is missing required members")
+	}
+	if ret.ExpiresIn > 0 {
+		ret.Expiry = time.Now().Add(time.Duration(ret.ExpiresIn) * second)
+	}
+	return ret, nil
+}
@@ -0,0 +1,10 @@
+// Package device implements the OAuth 2.0 Device Authorization Grant
+// (RFC 8628) as an authorization.ProtectedResourceTokenSource for headless
+// CLI clients that cannot open a loopback browser.
+//
+// A TokenSource requests device and user codes from the
+// device_authorization_endpoint, surfaces the verification URI through a
+// PromptFunc, polls the token endpoint honoring interval and slow_down, and
+// caches the issued tokens per (resource, scope), refreshing them when they
+// expire.
+package device
@@ -0,0 +1,48 @@
+package device
+
+import (
+	"net/http"
+
+	"github.com/viant/mcp-protocol/oauth2/registration"
+	"github.com/viant/mcp-protocol/oauth2/store"
+)
+
+// Option customizes a TokenSource.
+type Option func(s *TokenSource)
+
+// WithClient sets a pre-registered client identity; clientSecret is empty for public clients.
+func WithClient(clientID, clientSecret string) Option {
+	return func(s *TokenSource) {
+		s.clientID = clientID
+		s.clientSecret = clientSecret
+	}
+}
+
+// WithRegistration enables dynamic client registration when no client identity is configured.
+func WithRegistration(registrar *registration.Client, clientName string) Option {
+	return func(s *TokenSource) {
+		s.registrar = registrar
+		s.clientName = clientName
+	}
+}
+
+// WithPrompt sets how the verification URI and user code are presented; Prompt by default.
+func WithPrompt(prompt PromptFunc) Option {
+	return func(s *TokenSource) {
+		s.prompt = prompt
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for discovery and token requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *TokenSource) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithStore persists issued tokens so that they survive restarts and are shared between processes.
+func WithStore(tokens store.TokenStore) Option {
+	return func(s *TokenSource) {
+		s.store = tokens
+	}
+}
@@ -0,0 +1,250 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/oauth2/registration"
+	"github.com/viant/mcp-protocol/oauth2/store"
+	"github.com/viant/mcp-protocol/syncmap"
+	"golang.org/x/oauth2"
+)
+
+var _ authorization.ProtectedResourceTokenSource = (*TokenSource)(nil)
+
+// defaultInterval is the polling interval in seconds used when the server does not provide one (RFC 8628 §3.2).
+const defaultInterval = 5
+
+// second is the unit of the interval and expires_in members; tests shorten it.
+var second = time.Second
+
+// TokenSource obtains tokens for protected resources with the device authorization grant.
+type TokenSource struct {
+	clientID     string
+	clientSecret string
+	registrar    *registration.Client
+	clientName   string
+	prompt       PromptFunc
+	httpClient   *http.Client
+	store        store.TokenStore
+	servers      *syncmap.Map[string, *meta.AuthorizationServerMetadata]
+	tokens       *syncmap.Map[string, *entry]
+	locks        *syncmap.Locker[string]
+}
+
+// entry is a cached token together with what is needed to refresh it.
+type entry struct {
+	token  *oauth2.Token
+	server *meta.AuthorizationServerMetadata
+	auth   grant.Authenticator
+}
+
+// ProtectedResourceToken returns a cached, refreshed or newly authorized token for the resource and scope.
+// When scope is empty, all scopes advertised by the resource are requested.
+func (s *TokenSource) ProtectedResourceToken(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope string) (*oauth2.Token, error) {
+	if protectedResource == nil {
+		return nil, errors.New("protected resource metadata was nil")
+	}
+	if scope == "" {
+		scope = strings.Join(protectedResource.ScopesSupported, " ")
+	}
+	details, err := authorization.EncodeAuthorizationDetails(authorization.AuthorizationDetailsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	key := protectedResource.Resource + "\n" + scope + "\n" + details
+
+	// only requests for the same token wait on a device authorization in progress
+	unlock := s.locks.Lock(key)
+	defer unlock()
+	if cached, ok := s.tokens.Get(key); ok {
+		if cached.token.Valid() {
+			return cached.token, nil
+		}
+		if cached.token.RefreshToken != "" && s.store == nil {
+			refreshed, err := grant.RefreshToken(ctx, s.httpClient, cached.server.TokenEndpoint, cached.token, url.Values{"resource": {protectedResource.Resource}}, cached.auth)
+			if err == nil {
+				s.tokens.Put(key, &entry{token: refreshed, server: cached.server, auth: cached.auth})
+				return refreshed, nil
+			}
+		}
+		s.tokens.Delete(key)
+	}
+	if s.store != nil {
+		if cached := s.stored(ctx, protectedResource, scope, details); cached != nil {
+			s.tokens.Put(key, cached)
+			return cached.token, nil
+		}
+	}
+
+	if len(protectedResource.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("protected resource %s has no authorization_servers", protectedResource.Resource)
+	}
+	server, err := s.discover(ctx, protectedResource.AuthorizationServers[0])
+	if err != nil {
+		return nil, err
+	}
+	if server.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("authorization server %s does not support the device authorization grant", server.Issuer)
+	}
+	auth, err := s.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{"resource": {protectedResource.Resource}}
+	if scope != "" {
+		params.Set("scope", scope)
+	}
+	if details != "" {
+		params.Set(authorization.AuthorizationDetailsParameter, details)
+	}
+	deviceAuthorization, err := requestAuthorization(ctx, s.httpClient, server.DeviceAuthorizationEndpoint, params, auth)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	if err := s.prompt(ctx, deviceAuthorization); err != nil {
+		return nil, err
+	}
+	token, err := s.poll(ctx, server, deviceAuthorization, protectedResource.Resource, auth)
+	if err != nil {
+		return nil, err
+	}
+	s.tokens.Put(key, &entry{token: token, server: server, auth: auth})
+	if s.store != nil {
+		// a failure to persist does not invalidate the freshly issued token
+		_ = s.store.Put(ctx, store.Key{Issuer: protectedResource.AuthorizationServers[0], Resource: protectedResource.Resource, Scope: scope, AuthorizationDetails: details}, token)
+	}
+	return token, nil
+}
+
+// stored returns a valid token from the token store, refreshing it under the store lock so that
+// a rotated refresh token is never used twice across processes.
+func (s *TokenSource) stored(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope, details string) *entry {
+	if len(protectedResource.AuthorizationServers) == 0 {
+		return nil
+	}
+	issuer := protectedResource.AuthorizationServers[0]
+	server, err := s.discover(ctx, issuer)
+	if err != nil {
+		return nil
+	}
+	auth, err := s.client(ctx, server)
+	if err != nil {
+		return nil
+	}
+	key := store.Key{Issuer: issuer, Resource: protectedResource.Resource, Scope: scope, AuthorizationDetails: details}
+	token, err := s.store.Update(ctx, key, func(current *oauth2.Token) (*oauth2.Token, error) {
+		if current == nil || current.Valid() {
+			return current, nil
+		}
+		if current.RefreshToken == "" {
+			return nil, nil
+		}
+		refreshed, err := grant.RefreshToken(ctx, s.httpClient, server.TokenEndpoint, current, url.Values{"resource": {protectedResource.Resource}}, auth)
+		if err != nil {
+			return nil, nil // the stored grant is no longer usable
+		}
+		return refreshed, nil
+	})
+	if err != nil || token == nil {
+		return nil
+	}
+	return &entry{token: token, server: server, auth: auth}
+}
+
+// poll polls the token endpoint until the user completes authorization (RFC 8628 §3.4, §3.5).
+func (s *TokenSource) poll(ctx context.Context, server *meta.AuthorizationServerMetadata, deviceAuthorization *Authorization, resource string, auth grant.Authenticator) (*oauth2.Token, error) {
+	interval := defaultInterval * second
+	if deviceAuthorization.Interval > 0 {
+		interval = time.Duration(deviceAuthorization.Interval) * second
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if !deviceAuthorization.Expiry.IsZero() && time.Now().After(deviceAuthorization.Expiry) {
+			return nil, &grant.Error{Code: ErrorExpiredToken, Description: "device code expired before authorization completed"}
+		}
+		token, err := grant.Request(ctx, s.httpClient, server.TokenEndpoint, url.Values{
+			"grant_type":  {GrantType},
+			"device_code": {deviceAuthorization.DeviceCode},
+			"resource":    {resource},
+		}, auth)
+		if err == nil {
+			return token, nil
+		}
+		var grantErr *grant.Error
+		if !errors.As(err, &grantErr) {
+			return nil, err
+		}
+		switch grantErr.Code {
+		case ErrorAuthorizationPending:
+		case ErrorSlowDown:
+			interval += 5 * second
+		default:
+			return nil, fmt.Errorf("device authorization failed: %w", err)
+		}
+	}
+}
+
+func (s *TokenSource) discover(ctx context.Context, issuer string) (*meta.AuthorizationServerMetadata, error) {
+	if server, ok := s.servers.Get(issuer); ok {
+		return server, nil
+	}
+	server, err := meta.FetchAuthorizationServerMetadata(ctx, issuer, s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	s.servers.Put(issuer, server)
+	return server, nil
+}
+
+// client returns the token endpoint authenticator, registering a client when needed.
+func (s *TokenSource) client(ctx context.Context, server *meta.AuthorizationServerMetadata) (grant.Authenticator, error) {
+	if s.clientID != "" {
+		return grant.NewAuthenticator("", s.clientID, s.clientSecret)
+	}
+	if s.registrar == nil {
+		return nil, errors.New("client_id was not configured and dynamic registration is disabled")
+	}
+	metadata := &registration.ClientMetadata{
+		ClientName:              s.clientName,
+		GrantTypes:              []string{GrantType, registration.GrantTypeRefreshToken},
+		TokenEndpointAuthMethod: registration.AuthMethodNone,
+	}
+	metadata.Negotiate(server, registration.AuthMethodNone, registration.AuthMethodClientSecretBasic, registration.AuthMethodClientSecretPost)
+	info, err := s.registrar.Ensure(ctx, server, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register client: %w", err)
+	}
+	return grant.NewAuthenticator(info.TokenEndpointAuthMethod, info.ClientID, info.ClientSecret)
+}
+
+// New creates a device authorization TokenSource.
+func New(options ...Option) *TokenSource {
+	ret := &TokenSource{
+		prompt:  Prompt,
+		servers: syncmap.NewMap[string, *meta.AuthorizationServerMetadata](),
+		tokens:  syncmap.NewMap[string, *entry](),
+		locks:   syncmap.NewLocker[string](),
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	return ret
+}
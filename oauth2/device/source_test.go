@@ -0,0 +1,122 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/oauth2/store"
+)
+
+// newAuthorizationServer serves device authorization with expiresIn and answers token polls with responses in order,
+// repeating the last one; it records the time of every poll.
+func newAuthorizationServer(t *testing.T, expiresIn int, responses ...string) (*httptest.Server, *[]time.Time) {
+	var issuer string
+	var mux sync.Mutex
+	var polls []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/oauth-authorization-server":
+			_ = json.NewEncoder(w).Encode(meta.AuthorizationServerMetadata{
+				Issuer:                      issuer,
+				TokenEndpoint:               issuer + "/token",
+				DeviceAuthorizationEndpoint: issuer + "/device",
+			})
+		case "/device":
+			_ = json.NewEncoder(w).Encode(Authorization{DeviceCode: "dc", UserCode: "UC", VerificationURI: issuer + "/verify", ExpiresIn: int64(expiresIn), Interval: 1})
+		case "/token":
+			_ = r.ParseForm()
+			assert.Equal(t, GrantType, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "dc", r.PostForm.Get("device_code"))
+			mux.Lock()
+			polls = append(polls, time.Now())
+			response := responses[min(len(polls), len(responses))-1]
+			mux.Unlock()
+			if response[0] != '{' {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(grant.Error{Code: response})
+				return
+			}
+			_, _ = w.Write([]byte(response))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	issuer = srv.URL
+	return srv, &polls
+}
+
+func useSecond(t *testing.T, unit time.Duration) {
+	previous := second
+	second = unit
+	t.Cleanup(func() { second = previous })
+}
+
+func TestTokenSource_ProtectedResourceToken(t *testing.T) {
+	useSecond(t, 20*time.Millisecond)
+	srv, polls := newAuthorizationServer(t, 60, ErrorAuthorizationPending, ErrorSlowDown, `{"access_token":"at","token_type":"Bearer","expires_in":3600}`)
+	var prompted *Authorization
+	source := New(WithClient("client-1", ""), WithHTTPClient(srv.Client()), WithPrompt(func(_ context.Context, authorization *Authorization) error {
+		prompted = authorization
+		return nil
+	}))
+	resource := &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com", AuthorizationServers: []string{srv.URL}}
+
+	token, err := source.ProtectedResourceToken(context.Background(), resource, "read")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "at", token.AccessToken)
+	assert.Equal(t, "UC", prompted.UserCode)
+	if assert.Len(t, *polls, 3) {
+		// slow_down increases the interval of 1s by 5s (RFC 8628 §3.5)
+		assert.GreaterOrEqual(t, (*polls)[2].Sub((*polls)[1]), 6*second)
+	}
+
+	cached, err := source.ProtectedResourceToken(context.Background(), resource, "read")
+	assert.NoError(t, err)
+	assert.Same(t, token, cached)
+	assert.Len(t, *polls, 3)
+}
+
+func TestTokenSource_ProtectedResourceToken_Expired(t *testing.T) {
+	useSecond(t, 10*time.Millisecond)
+	srv, polls := newAuthorizationServer(t, 3, ErrorAuthorizationPending)
+	source := New(WithClient("client-1", ""), WithHTTPClient(srv.Client()), WithPrompt(func(context.Context, *Authorization) error { return nil }))
+	resource := &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com", AuthorizationServers: []string{srv.URL}}
+
+	_, err := source.ProtectedResourceToken(context.Background(), resource, "read")
+	var grantErr *grant.Error
+	if assert.True(t, errors.As(err, &grantErr)) {
+		assert.Equal(t, ErrorExpiredToken, grantErr.Code)
+	}
+	assert.LessOrEqual(t, len(*polls), 3)
+}
+
+func TestTokenSource_ProtectedResourceToken_Store(t *testing.T) {
+	useSecond(t, time.Millisecond)
+	srv, polls := newAuthorizationServer(t, 60, `{"access_token":"at","token_type":"Bearer","expires_in":3600}`)
+	tokens := store.NewMemoryStore()
+	newSource := func() *TokenSource {
+		return New(WithClient("client-1", ""), WithHTTPClient(srv.Client()), WithStore(tokens), WithPrompt(func(context.Context, *Authorization) error { return nil }))
+	}
+	resource := &meta.ProtectedResourceMetadata{Resource: "https://mcp.example.com", AuthorizationServers: []string{srv.URL}}
+
+	token, err := newSource().ProtectedResourceToken(context.Background(), resource, "read")
+	assert.NoError(t, err)
+	// a new source, e.g. after a restart, reuses the stored token without another device authorization
+	restored, err := newSource().ProtectedResourceToken(context.Background(), resource, "read")
+	assert.NoError(t, err)
+	assert.Equal(t, token.AccessToken, restored.AccessToken)
+	assert.Len(t, *polls, 1)
+}
@@ -0,0 +1,15 @@
+// Package dpop implements OAuth 2.0 Demonstrating Proof of Possession (DPoP,
+// RFC 9449).
+//
+// Clients use a Proofer to create proof JWTs bound to an ephemeral (or
+// supplied) key, either directly or through Transport, which signs every
+// outgoing request, converts bearer authorization into DPoP authorization and
+// retries once when the server demands a fresh nonce.
+//
+// Servers use a Verifier to validate proofs against the request, the access
+// token (ath) and the token's cnf.jkt thumbprint, rejecting replays via a
+// ReplayCache, so protected resources can require sender-constrained tokens as
+// advertised by ProtectedResourceMetadata.DPOPBoundAccessTokensRequired.
+// Behind a reverse proxy, configure WithBaseURL or WithForwardedHeaders so
+// that the proof htu is compared with the URL the client actually addressed.
+package dpop
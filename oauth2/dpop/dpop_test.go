@@ -0,0 +1,95 @@
+package dpop
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	proofer, err := NewProofer(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	const endpoint = "https://mcp.example.com/mcp"
+	testCases := []struct {
+		name        string
+		method      string
+		proofURL    string
+		accessToken string
+		verifyURL   string
+		verifyToken string
+		jkt         string
+		replay      bool
+		expectErr   bool
+	}{
+		{name: "valid bound proof", method: "POST", proofURL: endpoint + "?x=1", accessToken: "at", verifyURL: endpoint, verifyToken: "at", jkt: proofer.Thumbprint()},
+		{name: "valid default port", method: "POST", proofURL: "https://MCP.example.com:443/mcp", verifyURL: endpoint},
+		{name: "htu mismatch", method: "POST", proofURL: endpoint + "/other", verifyURL: endpoint, expectErr: true},
+		{name: "ath mismatch", method: "POST", proofURL: endpoint, accessToken: "at", verifyURL: endpoint, verifyToken: "other", jkt: proofer.Thumbprint(), expectErr: true},
+		{name: "unbound access token", method: "POST", proofURL: endpoint, accessToken: "at", verifyURL: endpoint, verifyToken: "at", expectErr: true},
+		{name: "jkt mismatch", method: "POST", proofURL: endpoint, verifyURL: endpoint, jkt: "other", expectErr: true},
+		{name: "replay", method: "POST", proofURL: endpoint, verifyURL: endpoint, replay: true, expectErr: true},
+	}
+	for _, tc := range testCases {
+		verifier := NewVerifier()
+		proof, err := proofer.Proof(tc.method, tc.proofURL, tc.accessToken)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		if tc.replay {
+			_, err = verifier.Verify(context.Background(), proof, tc.method, tc.verifyURL, tc.verifyToken, tc.jkt)
+			assert.NoError(t, err, tc.name)
+		}
+		actual, err := verifier.Verify(context.Background(), proof, tc.method, tc.verifyURL, tc.verifyToken, tc.jkt)
+		if tc.expectErr {
+			assert.True(t, errors.Is(err, ErrInvalidProof), tc.name)
+			continue
+		}
+		if assert.NoError(t, err, tc.name) {
+			assert.Equal(t, proofer.Thumbprint(), actual.Thumbprint, tc.name)
+		}
+	}
+}
+
+func TestVerifier_VerifyRequest(t *testing.T) {
+	proofer, err := NewProofer(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	testCases := []struct {
+		name      string
+		options   []VerifierOption
+		proofURL  string
+		header    http.Header
+		expectErr bool
+	}{
+		{name: "direct", proofURL: "http://10.0.0.1:8080/mcp"},
+		{name: "behind proxy", proofURL: "https://mcp.example.com/mcp", expectErr: true},
+		{name: "base url", options: []VerifierOption{WithBaseURL("https://mcp.example.com/")}, proofURL: "https://mcp.example.com/mcp"},
+		{name: "base url with prefix", options: []VerifierOption{WithBaseURL("https://example.com/tools")}, proofURL: "https://example.com/tools/mcp"},
+		{name: "forwarded", options: []VerifierOption{WithForwardedHeaders()}, header: http.Header{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"mcp.example.com, 10.0.0.2"}}, proofURL: "https://mcp.example.com/mcp"},
+		{name: "forwarded not trusted", header: http.Header{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"mcp.example.com"}}, proofURL: "https://mcp.example.com/mcp", expectErr: true},
+	}
+	for _, tc := range testCases {
+		proof, err := proofer.Proof(http.MethodPost, tc.proofURL, "")
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		request := httptest.NewRequest(http.MethodPost, "http://10.0.0.1:8080/mcp", nil)
+		for k, v := range tc.header {
+			request.Header[k] = v
+		}
+		request.Header.Set(HeaderDPoP, proof)
+		_, err = NewVerifier(tc.options...).VerifyRequest(request, "", "")
+		if tc.expectErr {
+			assert.ErrorIs(t, err, ErrInvalidProof, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+}
@@ -0,0 +1,56 @@
+package dpop
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// VerifierOption customizes a Verifier.
+type VerifierOption func(v *Verifier)
+
+// WithAlgorithms restricts accepted proof algorithms.
+func WithAlgorithms(algorithms ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.algorithms = algorithms
+	}
+}
+
+// WithMaxAge sets how old a proof (by iat) may be, and the tolerated clock skew for proofs from the future.
+func WithMaxAge(maxAge, skew time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+		v.skew = skew
+	}
+}
+
+// WithReplayCache sets the cache used to detect replayed proofs, e.g. a shared cache for clustered servers.
+func WithReplayCache(cache ReplayCache) VerifierOption {
+	return func(v *Verifier) {
+		v.replay = cache
+	}
+}
+
+// WithNonce requires proofs to carry the nonce returned by current; an empty nonce disables the check.
+// Servers send the current nonce to clients in the DPoP-Nonce response header.
+func WithNonce(current func(ctx context.Context) string) VerifierOption {
+	return func(v *Verifier) {
+		v.nonce = current
+	}
+}
+
+// WithBaseURL sets the external scheme, host and any path prefix stripped by a reverse proxy, e.g.
+// "https://mcp.example.com"; VerifyRequest then matches htu against baseURL followed by the request URI.
+func WithBaseURL(baseURL string) VerifierOption {
+	return func(v *Verifier) {
+		v.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithForwardedHeaders makes VerifyRequest take the scheme and host from the X-Forwarded-Proto and
+// X-Forwarded-Host headers. Enable it only behind a proxy that sets them, since clients can forge them.
+func WithForwardedHeaders() VerifierOption {
+	return func(v *Verifier) {
+		v.forwarded = true
+	}
+}
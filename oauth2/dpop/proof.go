@@ -0,0 +1,170 @@
+package dpop
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/jws"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Header names and token type defined by RFC 9449.
+const (
+	HeaderDPoP  = "DPoP"
+	HeaderNonce = "DPoP-Nonce"
+	TokenType   = "DPoP"
+	proofType   = "dpop+jwt"
+)
+
+// Claims represents the DPoP proof JWT claims (RFC 9449 §4.2).
+type Claims struct {
+	JTI   string `json:"jti"`
+	HTM   string `json:"htm"`
+	HTU   string `json:"htu"`
+	IAT   int64  `json:"iat"`
+	ATH   string `json:"ath,omitempty"`
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// Proofer creates DPoP proofs with a single key pair and remembers server-provided nonces per origin.
+type Proofer struct {
+	key        crypto.Signer
+	algorithm  string
+	jwk        *meta.JSONWebKey
+	thumbprint string
+	nonces     *syncmap.Map[string, string]
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the proof key, used as dpop_jkt and cnf.jkt.
+func (p *Proofer) Thumbprint() string {
+	return p.thumbprint
+}
+
+// Algorithm returns the proof signature algorithm.
+func (p *Proofer) Algorithm() string {
+	return p.algorithm
+}
+
+// Proof creates a proof for an HTTP request. When accessToken is not empty the ath claim binds the
+// proof to it; the most recent nonce received from the target origin is included automatically.
+func (p *Proofer) Proof(method, URL, accessToken string) (string, error) {
+	htu, err := normalizeURL(URL)
+	if err != nil {
+		return "", err
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := &Claims{
+		JTI: base64.RawURLEncoding.EncodeToString(jti),
+		HTM: strings.ToUpper(method),
+		HTU: htu,
+		IAT: time.Now().Unix(),
+	}
+	if accessToken != "" {
+		claims.ATH = AccessTokenHash(accessToken)
+	}
+	if nonce, ok := p.nonces.Get(origin(htu)); ok {
+		claims.Nonce = nonce
+	}
+	return jws.Sign(jws.Header{Algorithm: p.algorithm, Type: proofType, JWK: p.jwk}, claims, p.key)
+}
+
+// UpdateNonce stores the DPoP-Nonce returned by the server for the URL's origin, reporting whether it changed.
+func (p *Proofer) UpdateNonce(URL string, header http.Header) bool {
+	nonce := header.Get(HeaderNonce)
+	if nonce == "" {
+		return false
+	}
+	htu, err := normalizeURL(URL)
+	if err != nil {
+		return false
+	}
+	key := origin(htu)
+	if previous, ok := p.nonces.Get(key); ok && previous == nonce {
+		return false
+	}
+	p.nonces.Put(key, nonce)
+	return true
+}
+
+// AccessTokenHash returns the ath claim value for an access token (RFC 9449 §4.2).
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// normalizeURL returns the htu form of a URL: without query and fragment, with lower-case scheme
+// and host and without default ports (RFC 9449 §4.3).
+func normalizeURL(URL string) (string, error) {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid htu: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("htu %q is not absolute", URL)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "https" && u.Port() == "443") || (u.Scheme == "http" && u.Port() == "80") {
+		u.Host = u.Hostname()
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+	return u.String(), nil
+}
+
+func origin(htu string) string {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return htu
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// NewProofer creates a Proofer signing with key; when key is nil an ephemeral P-256 key is generated.
+func NewProofer(key crypto.Signer) (*Proofer, error) {
+	if key == nil {
+		ephemeral, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate DPoP key: %w", err)
+		}
+		key = ephemeral
+	}
+	algorithm, err := jws.DefaultAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := meta.NewJSONWebKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	jkt, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	return &Proofer{
+		key:        key,
+		algorithm:  algorithm,
+		jwk:        jwk,
+		thumbprint: jkt,
+		nonces:     syncmap.NewMap[string, string](),
+	}, nil
+}
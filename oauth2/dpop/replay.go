@@ -0,0 +1,44 @@
+package dpop
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache records proof identifiers to reject replayed DPoP proofs (RFC 9449 §11.1).
+type ReplayCache interface {
+	// Seen records jti until expiry and reports whether it had already been recorded.
+	Seen(jti string, expiry time.Time) bool
+}
+
+// MemoryReplayCache is an in-process ReplayCache; expired entries are purged as new ones are recorded.
+type MemoryReplayCache struct {
+	entries   map[string]time.Time
+	nextPurge time.Time
+	mux       sync.Mutex
+}
+
+// Seen records jti until expiry and reports whether it had already been recorded.
+func (c *MemoryReplayCache) Seen(jti string, expiry time.Time) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	now := time.Now()
+	if now.After(c.nextPurge) {
+		for k, v := range c.entries {
+			if now.After(v) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextPurge = now.Add(time.Minute)
+	}
+	if until, ok := c.entries[jti]; ok && now.Before(until) {
+		return true
+	}
+	c.entries[jti] = expiry
+	return false
+}
+
+// NewMemoryReplayCache creates an in-process ReplayCache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{entries: map[string]time.Time{}}
+}
@@ -0,0 +1,72 @@
+package dpop
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Transport is an http.RoundTripper adding a DPoP proof to every request. Requests authorized with
+// the DPoP scheme get an ath-bound proof; others (e.g. token endpoint requests) get an unbound proof.
+// When the server answers 400 or 401 with a new DPoP-Nonce, the request is retried once.
+type Transport struct {
+	Proofer *Proofer
+	// Base is the underlying RoundTripper; http.DefaultTransport when nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed, err := t.sign(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base().RoundTrip(signed)
+	if err != nil {
+		return nil, err
+	}
+	changed := t.Proofer.UpdateNonce(req.URL.String(), resp.Header)
+	if !changed || (resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized) {
+		return resp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // body cannot be replayed
+	}
+	retry, err := t.sign(req)
+	if err != nil {
+		return resp, nil
+	}
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_ = resp.Body.Close()
+	resp, err = t.base().RoundTrip(retry)
+	if err != nil {
+		return nil, err
+	}
+	t.Proofer.UpdateNonce(req.URL.String(), resp.Header)
+	return resp, nil
+}
+
+// sign returns a copy of req carrying a fresh proof, as required by the http.RoundTripper contract.
+func (t *Transport) sign(req *http.Request) (*http.Request, error) {
+	var accessToken string
+	if scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, TokenType) {
+		accessToken = strings.TrimSpace(token)
+	}
+	proof, err := t.Proofer.Proof(req.Method, req.URL.String(), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	ret := req.Clone(req.Context())
+	ret.Header.Set(HeaderDPoP, proof)
+	return ret, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,200 @@
+package dpop
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/challenge"
+	"github.com/viant/mcp-protocol/oauth2/jws"
+)
+
+// Error codes used in DPoP challenges (RFC 9449 §7.1, §8).
+const (
+	ErrorInvalidProof = "invalid_dpop_proof"
+	ErrorUseNonce     = "use_dpop_nonce"
+)
+
+var (
+	// ErrInvalidProof is returned for malformed or unverifiable proofs.
+	ErrInvalidProof = errors.New(ErrorInvalidProof)
+	// ErrUseNonce is returned when the proof lacks the current server nonce.
+	ErrUseNonce = errors.New(ErrorUseNonce)
+)
+
+// Proof is a verified DPoP proof.
+type Proof struct {
+	Claims
+	// Thumbprint is the RFC 7638 thumbprint of the proof key.
+	Thumbprint string
+}
+
+// Verifier validates DPoP proofs presented to a protected resource or authorization server.
+type Verifier struct {
+	algorithms []string
+	maxAge     time.Duration
+	skew       time.Duration
+	replay     ReplayCache
+	nonce      func(ctx context.Context) string
+	baseURL    string
+	forwarded  bool
+}
+
+// Algorithms returns the accepted proof algorithms, as advertised in dpop_signing_alg_values_supported.
+func (v *Verifier) Algorithms() []string {
+	return v.algorithms
+}
+
+// VerifyRequest verifies the single DPoP header of r. accessToken and jkt are the presented access
+// token and its cnf.jkt confirmation; both are empty for token endpoint requests.
+func (v *Verifier) VerifyRequest(r *http.Request, accessToken, jkt string) (*Proof, error) {
+	proofs := r.Header.Values(HeaderDPoP)
+	if len(proofs) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one %s header, got %d", ErrInvalidProof, HeaderDPoP, len(proofs))
+	}
+	return v.Verify(r.Context(), proofs[0], r.Method, v.requestURL(r), accessToken, jkt)
+}
+
+// requestURL reconstructs the URL the client addressed, which differs from what the server sees behind a proxy.
+func (v *Verifier) requestURL(r *http.Request) string {
+	if v.baseURL != "" {
+		return v.baseURL + r.URL.RequestURI()
+	}
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if v.forwarded {
+		if proto := forwardedValue(r.Header.Get("X-Forwarded-Proto")); proto != "" {
+			scheme = proto
+		}
+		if forwardedHost := forwardedValue(r.Header.Get("X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// forwardedValue returns the value set by the proxy closest to the client.
+func forwardedValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
+
+// Verify verifies a proof against the HTTP method and URL of the request it accompanies (RFC 9449 §4.3).
+// A presented access token must be bound to the proof key, so accessToken requires jkt.
+func (v *Verifier) Verify(ctx context.Context, proof, method, requestURL, accessToken, jkt string) (*Proof, error) {
+	if accessToken != "" && jkt == "" {
+		return nil, fmt.Errorf("%w: access token is not bound to a proof key", ErrInvalidProof)
+	}
+	token, err := jws.Parse(proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if token.Header.Type != proofType {
+		return nil, fmt.Errorf("%w: unexpected typ %q", ErrInvalidProof, token.Header.Type)
+	}
+	if !slices.Contains(v.algorithms, token.Header.Algorithm) {
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidProof, token.Header.Algorithm)
+	}
+	jwk := token.Header.JWK
+	if jwk == nil {
+		return nil, fmt.Errorf("%w: missing jwk header", ErrInvalidProof)
+	}
+	if _, private := jwk.Extra["d"]; private || jwk.K != "" {
+		return nil, fmt.Errorf("%w: jwk contains private key material", ErrInvalidProof)
+	}
+	publicKey, err := jwk.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if err := token.Verify(publicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	ret := &Proof{}
+	if err := token.Claims(&ret.Claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if ret.JTI == "" {
+		return nil, fmt.Errorf("%w: missing jti", ErrInvalidProof)
+	}
+	if ret.HTM != method {
+		return nil, fmt.Errorf("%w: htm %q does not match %q", ErrInvalidProof, ret.HTM, method)
+	}
+	expectedHTU, err := normalizeURL(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if actualHTU, err := normalizeURL(ret.HTU); err != nil || actualHTU != expectedHTU {
+		return nil, fmt.Errorf("%w: htu %q does not match %q", ErrInvalidProof, ret.HTU, expectedHTU)
+	}
+	issuedAt := time.Unix(ret.IAT, 0)
+	now := time.Now()
+	if issuedAt.After(now.Add(v.skew)) || issuedAt.Before(now.Add(-v.maxAge)) {
+		return nil, fmt.Errorf("%w: iat outside of acceptable window", ErrInvalidProof)
+	}
+	if v.nonce != nil {
+		if expected := v.nonce(ctx); expected != "" && ret.Nonce != expected {
+			return nil, ErrUseNonce
+		}
+	}
+	if accessToken != "" {
+		if subtle.ConstantTimeCompare([]byte(ret.ATH), []byte(AccessTokenHash(accessToken))) != 1 {
+			return nil, fmt.Errorf("%w: ath does not match access token", ErrInvalidProof)
+		}
+	}
+	if ret.Thumbprint, err = jwk.Thumbprint(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if jkt != "" && subtle.ConstantTimeCompare([]byte(ret.Thumbprint), []byte(jkt)) != 1 {
+		return nil, fmt.Errorf("%w: proof key does not match cnf.jkt", ErrInvalidProof)
+	}
+	// record the jti last so that rejected proofs do not poison the cache
+	if v.replay.Seen(ret.Thumbprint+":"+ret.JTI, issuedAt.Add(v.maxAge+v.skew)) {
+		return nil, fmt.Errorf("%w: proof replayed", ErrInvalidProof)
+	}
+	return ret, nil
+}
+
+// Challenge builds the DPoP WWW-Authenticate challenge for a verification error (RFC 9449 §7.1).
+func (v *Verifier) Challenge(err error) *challenge.Challenge {
+	ret := &challenge.Challenge{Scheme: TokenType, Params: map[string]string{"algs": strings.Join(v.algorithms, " ")}}
+	switch {
+	case errors.Is(err, ErrUseNonce):
+		ret.Params["error"] = ErrorUseNonce
+		ret.Params["error_description"] = "Resource server requires nonce in DPoP proof"
+	case err != nil:
+		ret.Params["error"] = ErrorInvalidProof
+		ret.Params["error_description"] = err.Error()
+	}
+	return ret
+}
+
+// ConfirmationThumbprint returns the cnf.jkt member of access token claims or introspection response (RFC 9449 §6).
+func ConfirmationThumbprint(claims map[string]any) string {
+	cnf, _ := claims["cnf"].(map[string]any)
+	jkt, _ := cnf["jkt"].(string)
+	return jkt
+}
+
+// NewVerifier creates a Verifier. By default all asymmetric jws.Algorithms are accepted, proofs may be
+// up to 5 minutes old, and replays are tracked by an in-memory cache.
+func NewVerifier(options ...VerifierOption) *Verifier {
+	ret := &Verifier{
+		algorithms: jws.Algorithms,
+		maxAge:     5 * time.Minute,
+		skew:       30 * time.Second,
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.replay == nil {
+		ret.replay = NewMemoryReplayCache()
+	}
+	return ret
+}
@@ -0,0 +1,100 @@
+package exchange
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// cache holds up to size exchanged tokens; when full, the token expiring first is evicted.
+type cache struct {
+	size    int
+	ttl     time.Duration
+	mux     sync.Mutex
+	entries map[string]*entry
+	expiry  expiryHeap
+}
+
+type entry struct {
+	key     string
+	token   *oauth2.Token
+	expires time.Time
+	index   int
+}
+
+// get returns the valid token cached under key, or nil.
+func (c *cache) get(key string) *oauth2.Token {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !e.token.Valid() || time.Now().After(e.expires) {
+		c.remove(e)
+		return nil
+	}
+	return e.token
+}
+
+// put caches token under key; a token without expiry is kept for the cache ttl.
+func (c *cache) put(key string, token *oauth2.Token) {
+	expires := token.Expiry
+	if expires.IsZero() {
+		expires = time.Now().Add(c.ttl)
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.token, e.expires = token, expires
+		heap.Fix(&c.expiry, e.index)
+		return
+	}
+	for len(c.entries) >= c.size && len(c.expiry) > 0 {
+		c.remove(c.expiry[0])
+	}
+	e := &entry{key: key, token: token, expires: expires}
+	heap.Push(&c.expiry, e)
+	c.entries[key] = e
+}
+
+func (c *cache) remove(e *entry) {
+	heap.Remove(&c.expiry, e.index)
+	delete(c.entries, e.key)
+}
+
+func (c *cache) len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return len(c.entries)
+}
+
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{size: size, ttl: ttl, entries: map[string]*entry{}}
+}
+
+// expiryHeap orders cache entries by expiry, earliest first.
+type expiryHeap []*entry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *expiryHeap) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,10 @@
+// Package exchange implements OAuth 2.0 Token Exchange (RFC 8693) for calls
+// an MCP server makes to downstream APIs on behalf of the user.
+//
+// The MCP specification forbids passing the inbound access token through to
+// other services. A TokenSource instead takes the validated inbound token from
+// the context (authorization.TokenKey), exchanges it at the authorization
+// server token endpoint for a token audienced to the downstream resource, and
+// caches the result per subject and actor token, audience, resource, scope and
+// authorization details, so it can be used from any server.ToolHandlerFunc.
+package exchange
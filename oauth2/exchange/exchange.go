@@ -0,0 +1,194 @@
+package exchange
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"golang.org/x/oauth2"
+)
+
+// Token exchange identifiers (RFC 8693 §2.1, §3).
+const (
+	GrantType             = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// ErrNoInboundToken is returned when the context carries no inbound token to exchange.
+var ErrNoInboundToken = errors.New("no inbound token in context")
+
+var _ authorization.ProtectedResourceTokenSource = (*TokenSource)(nil)
+
+// Target describes the downstream service a token is requested for.
+type Target struct {
+	// Audience is the logical name of the target service (RFC 8693 §2.1 audience).
+	Audience string
+	// Resource is the absolute URI of the target service (RFC 8693 §2.1, RFC 8707).
+	Resource string
+	// Scope is the space-delimited scope requested for the downstream token.
+	Scope string
+}
+
+// TokenSource exchanges inbound tokens for downstream tokens.
+type TokenSource struct {
+	tokenEndpoint    string
+	auth             grant.Authenticator
+	httpClient       *http.Client
+	subjectTokenType string
+	requestedType    string
+	actorToken       func(ctx context.Context) (string, string, error)
+	cacheSize        int
+	cacheTTL         time.Duration
+	tokens           *cache
+}
+
+// Cache defaults; see WithCache.
+const (
+	defaultCacheSize = 1024
+	defaultCacheTTL  = 5 * time.Minute
+)
+
+// Token exchanges the inbound token from ctx for a token for target.
+func (s *TokenSource) Token(ctx context.Context, target Target) (*oauth2.Token, error) {
+	inbound, ok := authorization.TokenFromContext(ctx)
+	if !ok {
+		return nil, ErrNoInboundToken
+	}
+	return s.Exchange(ctx, inbound.Token, target)
+}
+
+// ProtectedResourceToken exchanges the inbound token from ctx for a token for the protected resource.
+func (s *TokenSource) ProtectedResourceToken(ctx context.Context, protectedResource *meta.ProtectedResourceMetadata, scope string) (*oauth2.Token, error) {
+	if protectedResource == nil {
+		return nil, errors.New("protected resource metadata was nil")
+	}
+	return s.Token(ctx, Target{Resource: protectedResource.Resource, Scope: scope})
+}
+
+// Exchange exchanges subjectToken for a token for target, reusing a cached token for the same
+// subject and actor token, audience, resource, scope and authorization details while it is valid.
+// Authorization details set on ctx with authorization.WithAuthorizationDetails are requested too (RFC 9396 §7).
+func (s *TokenSource) Exchange(ctx context.Context, subjectToken string, target Target) (*oauth2.Token, error) {
+	if subjectToken == "" {
+		return nil, errors.New("subject token was empty")
+	}
+	if target.Audience == "" && target.Resource == "" {
+		return nil, errors.New("target audience or resource is required")
+	}
+	var actorToken, actorTokenType string
+	if s.actorToken != nil {
+		var err error
+		if actorToken, actorTokenType, err = s.actorToken(ctx); err != nil {
+			return nil, fmt.Errorf("failed to obtain actor token: %w", err)
+		}
+	}
+	details, err := authorization.EncodeAuthorizationDetails(authorization.AuthorizationDetailsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	key := strings.Join([]string{tokenHash(subjectToken), tokenHash(actorToken), target.Audience, target.Resource, target.Scope, details}, "\n")
+	if cached := s.tokens.get(key); cached != nil {
+		return cached, nil
+	}
+
+	params := url.Values{
+		"grant_type":         {GrantType},
+		"subject_token":      {subjectToken},
+		"subject_token_type": {s.subjectTokenType},
+	}
+	if s.requestedType != "" {
+		params.Set("requested_token_type", s.requestedType)
+	}
+	if target.Audience != "" {
+		params.Set("audience", target.Audience)
+	}
+	if target.Resource != "" {
+		params.Set("resource", target.Resource)
+	}
+	if target.Scope != "" {
+		params.Set("scope", target.Scope)
+	}
+	if details != "" {
+		params.Set(authorization.AuthorizationDetailsParameter, details)
+	}
+	if actorToken != "" {
+		params.Set("actor_token", actorToken)
+		params.Set("actor_token_type", actorTokenType)
+	}
+	token, err := grant.Request(ctx, s.httpClient, s.tokenEndpoint, params, s.auth)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	// RFC 8693 §2.2.1: token_type "N_A" is used for tokens that are not access tokens
+	if strings.EqualFold(token.TokenType, "N_A") {
+		token.TokenType = "Bearer"
+	}
+	s.tokens.put(key, token)
+	return token, nil
+}
+
+// TokenSource returns an oauth2.TokenSource bound to the inbound token of ctx and target.
+func (s *TokenSource) TokenSource(ctx context.Context, target Target) oauth2.TokenSource {
+	return &boundSource{ctx: ctx, source: s, target: target}
+}
+
+// Client returns an HTTP client authorizing requests with tokens exchanged for target.
+func (s *TokenSource) Client(ctx context.Context, target Target) *http.Client {
+	return oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, s.httpClient), s.TokenSource(ctx, target))
+}
+
+type boundSource struct {
+	ctx    context.Context
+	source *TokenSource
+	target Target
+}
+
+func (b *boundSource) Token() (*oauth2.Token, error) {
+	return b.source.Token(b.ctx, b.target)
+}
+
+// tokenHash identifies a subject token. Claims of an inbound JWT are not verified here,
+// so the cache is keyed on the whole token rather than on its iss and sub claims.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// New creates a token exchange TokenSource for the authorization server, authenticating the
+// MCP server as a client with auth.
+func New(server *meta.AuthorizationServerMetadata, auth grant.Authenticator, options ...Option) (*TokenSource, error) {
+	if server == nil || server.TokenEndpoint == "" {
+		return nil, errors.New("authorization server token endpoint is required")
+	}
+	if len(server.GrantTypesSupported) > 0 && !slices.Contains(server.GrantTypesSupported, GrantType) {
+		return nil, fmt.Errorf("authorization server %s does not support token exchange", server.Issuer)
+	}
+	ret := &TokenSource{
+		tokenEndpoint:    server.TokenEndpoint,
+		auth:             auth,
+		subjectTokenType: TokenTypeAccessToken,
+		cacheSize:        defaultCacheSize,
+		cacheTTL:         defaultCacheTTL,
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	ret.tokens = newCache(ret.cacheSize, ret.cacheTTL)
+	return ret, nil
+}
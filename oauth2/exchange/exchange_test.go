@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/authorization"
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+func TestTokenSource_Exchange(t *testing.T) {
+	var requests atomic.Int32
+	var details atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		details.Store(r.FormValue(authorization.AuthorizationDetailsParameter))
+		expiresIn := 3600
+		if r.FormValue("scope") == "expired" {
+			expiresIn = 1 // within the expiry delta, so invalid once cached
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("downstream-%d", n),
+			"token_type":   "N_A",
+			"expires_in":   expiresIn,
+		})
+	}))
+	defer server.Close()
+
+	actor := "agent-1"
+	source, err := New(&meta.AuthorizationServerMetadata{TokenEndpoint: server.URL}, &grant.None{ClientID: "mcp"},
+		WithCache(3, 0), WithActorToken(func(context.Context) (string, string, error) {
+			return actor, TokenTypeAccessToken, nil
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx := context.Background()
+	target := Target{Resource: "https://api.example.com"}
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://as.example.com","sub":"alice"}`))
+	victim := "eyJhbGciOiJSUzI1NiJ9." + claims + ".c2lnMQ"
+	forged := "eyJhbGciOiJub25lIn0." + claims + ".c2lnMg"
+
+	victimToken, err := source.Exchange(ctx, victim, target)
+	assert.NoError(t, err)
+	forgedToken, err := source.Exchange(ctx, forged, target)
+	assert.NoError(t, err)
+	assert.NotEqual(t, victimToken.AccessToken, forgedToken.AccessToken)
+	assert.Equal(t, "Bearer", victimToken.TokenType)
+
+	cached, err := source.Exchange(ctx, victim, target)
+	assert.NoError(t, err)
+	assert.Equal(t, victimToken.AccessToken, cached.AccessToken)
+	assert.EqualValues(t, 2, requests.Load())
+
+	// authorization details are requested and distinguish cached tokens
+	detailsCtx := authorization.WithAuthorizationDetails(ctx, &authorization.AuthorizationDetail{Type: "payment", Actions: []string{"initiate"}})
+	detailsToken, err := source.Exchange(detailsCtx, victim, target)
+	assert.NoError(t, err)
+	assert.NotEqual(t, victimToken.AccessToken, detailsToken.AccessToken)
+	assert.JSONEq(t, `[{"type":"payment","actions":["initiate"]}]`, details.Load().(string))
+	assert.EqualValues(t, 3, requests.Load())
+
+	// a different actor does not reuse the token obtained on behalf of another one
+	actor = "agent-2"
+	actorToken, err := source.Exchange(ctx, victim, target)
+	assert.NoError(t, err)
+	assert.NotEqual(t, victimToken.AccessToken, actorToken.AccessToken)
+	assert.EqualValues(t, 4, requests.Load())
+
+	// the cache is full, so the token expiring first is evicted: the expired one goes before any valid one
+	_, err = source.Exchange(ctx, victim, Target{Resource: target.Resource, Scope: "expired"})
+	assert.NoError(t, err)
+	_, err = source.Exchange(ctx, victim, Target{Resource: target.Resource, Scope: "other"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, source.tokens.len())
+	_, err = source.Exchange(ctx, victim, Target{Resource: target.Resource, Scope: "expired"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, requests.Load())
+}
@@ -0,0 +1,51 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Option customizes a TokenSource.
+type Option func(s *TokenSource)
+
+// WithHTTPClient sets the HTTP client used for token requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *TokenSource) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithSubjectTokenType sets the type of the inbound token; TokenTypeAccessToken by default.
+func WithSubjectTokenType(tokenType string) Option {
+	return func(s *TokenSource) {
+		s.subjectTokenType = tokenType
+	}
+}
+
+// WithRequestedTokenType sets the requested_token_type parameter.
+func WithRequestedTokenType(tokenType string) Option {
+	return func(s *TokenSource) {
+		s.requestedType = tokenType
+	}
+}
+
+// WithActorToken supplies an actor token and its type for delegation semantics (RFC 8693 §1.1).
+func WithActorToken(actor func(ctx context.Context) (token, tokenType string, err error)) Option {
+	return func(s *TokenSource) {
+		s.actorToken = actor
+	}
+}
+
+// WithCache sets the maximum number of cached tokens and how long a token without expiry is cached;
+// 1024 tokens and 5 minutes by default.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(s *TokenSource) {
+		if size > 0 {
+			s.cacheSize = size
+		}
+		if ttl > 0 {
+			s.cacheTTL = ttl
+		}
+	}
+}
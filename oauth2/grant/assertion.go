@@ -0,0 +1,101 @@
+package grant
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/jws"
+)
+
+// Additional token endpoint client authentication methods.
+const (
+	AuthMethodPrivateKeyJWT = "private_key_jwt" // OpenID Connect Core §9, RFC 7523 §2.2
+	AuthMethodTLSClientAuth = "tls_client_auth" // RFC 8705 §2.1
+
+	// ClientAssertionTypeJWTBearer is the client_assertion_type for JWT client assertions (RFC 7523 §2.2).
+	ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// PrivateKeyJWT authenticates with a JWT assertion signed by the client's private key (RFC 7523 §2.2).
+// A fresh assertion with a unique jti is created for every request.
+type PrivateKeyJWT struct {
+	ClientID string
+	Key      crypto.Signer
+	// KeyID identifies the key in the client's registered JWK Set.
+	KeyID string
+	// Algorithm overrides the default algorithm for Key.
+	Algorithm string
+	// Audience overrides the aud claim; the token endpoint URL is used when empty.
+	Audience string
+	// Lifetime of the assertion; 1 minute when zero.
+	Lifetime time.Duration
+}
+
+// assertionClaims represents the JWT client assertion claims (RFC 7523 §3).
+type assertionClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	JTI       string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Authenticate adds the client_assertion and client_assertion_type parameters.
+func (a *PrivateKeyJWT) Authenticate(_ context.Context, endpoint string, _ http.Header, params url.Values) error {
+	if a.Key == nil {
+		return errors.New("private_key_jwt: signing key was nil")
+	}
+	assertion, err := a.Assertion(endpoint)
+	if err != nil {
+		return err
+	}
+	params.Set("client_id", a.ClientID)
+	params.Set("client_assertion_type", ClientAssertionTypeJWTBearer)
+	params.Set("client_assertion", assertion)
+	return nil
+}
+
+// Assertion creates a signed client assertion for the endpoint.
+func (a *PrivateKeyJWT) Assertion(endpoint string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	lifetime := a.Lifetime
+	if lifetime == 0 {
+		lifetime = time.Minute
+	}
+	audience := a.Audience
+	if audience == "" {
+		audience = endpoint
+	}
+	now := time.Now()
+	claims := &assertionClaims{
+		Issuer:    a.ClientID,
+		Subject:   a.ClientID,
+		Audience:  audience,
+		JTI:       base64.RawURLEncoding.EncodeToString(jti),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(lifetime).Unix(),
+	}
+	return jws.Sign(jws.Header{Algorithm: a.Algorithm, Type: "JWT", KeyID: a.KeyID}, claims, a.Key)
+}
+
+// TLSClientAuth authenticates with the client certificate presented during the TLS handshake (RFC 8705 §2).
+// The HTTP client used for token requests must be configured with the certificate.
+type TLSClientAuth struct {
+	ClientID string
+}
+
+// Authenticate adds the client_id parameter.
+func (a *TLSClientAuth) Authenticate(_ context.Context, _ string, _ http.Header, params url.Values) error {
+	params.Set("client_id", a.ClientID)
+	return nil
+}
@@ -0,0 +1,74 @@
+package grant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Token endpoint client authentication methods (RFC 7591 §2).
+const (
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+)
+
+// Authenticator applies client authentication to a token endpoint request.
+type Authenticator interface {
+	// Authenticate adds client credentials to the request header or form parameters.
+	Authenticate(ctx context.Context, endpoint string, header http.Header, params url.Values) error
+}
+
+// None identifies a public client by its client_id only.
+type None struct {
+	ClientID string
+}
+
+// Authenticate adds the client_id parameter.
+func (a *None) Authenticate(_ context.Context, _ string, _ http.Header, params url.Values) error {
+	params.Set("client_id", a.ClientID)
+	return nil
+}
+
+// ClientSecretBasic authenticates with HTTP Basic authentication (RFC 6749 §2.3.1).
+type ClientSecretBasic struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Authenticate sets the Authorization header with form-encoded credentials.
+func (a *ClientSecretBasic) Authenticate(_ context.Context, _ string, header http.Header, _ url.Values) error {
+	req := &http.Request{Header: header}
+	req.SetBasicAuth(url.QueryEscape(a.ClientID), url.QueryEscape(a.ClientSecret))
+	return nil
+}
+
+// ClientSecretPost authenticates with client credentials in the request body (RFC 6749 §2.3.1).
+type ClientSecretPost struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Authenticate adds the client_id and client_secret parameters.
+func (a *ClientSecretPost) Authenticate(_ context.Context, _ string, _ http.Header, params url.Values) error {
+	params.Set("client_id", a.ClientID)
+	params.Set("client_secret", a.ClientSecret)
+	return nil
+}
+
+// NewAuthenticator creates an Authenticator for a secret-based authentication method.
+func NewAuthenticator(method, clientID, clientSecret string) (Authenticator, error) {
+	switch method {
+	case AuthMethodNone:
+		return &None{ClientID: clientID}, nil
+	case AuthMethodClientSecretBasic, "":
+		if clientSecret == "" {
+			return &None{ClientID: clientID}, nil
+		}
+		return &ClientSecretBasic{ClientID: clientID, ClientSecret: clientSecret}, nil
+	case AuthMethodClientSecretPost:
+		return &ClientSecretPost{ClientID: clientID, ClientSecret: clientSecret}, nil
+	}
+	return nil, fmt.Errorf("unsupported token endpoint auth method: %s", method)
+}
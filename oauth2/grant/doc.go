@@ -0,0 +1,12 @@
+// Package grant implements OAuth 2.0 token endpoint requests (RFC 6749 §3.2)
+// shared by the token sources in this module.
+//
+// It posts grant parameters to a token endpoint, applies client
+// authentication via an Authenticator, and decodes the access token response
+// (including extension members such as id_token or issued_token_type) into a
+// golang.org/x/oauth2 Token, or the error response into an Error.
+//
+// Authenticators cover none, client_secret_basic, client_secret_post,
+// private_key_jwt (RFC 7523) and tls_client_auth (RFC 8705); Negotiate picks
+// one against the authorization server metadata.
+package grant
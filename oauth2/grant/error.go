@@ -0,0 +1,34 @@
+package grant
+
+import "fmt"
+
+// Error codes defined by RFC 6749 §5.2 and extensions used by this module.
+const (
+	ErrorInvalidRequest       = "invalid_request"
+	ErrorInvalidClient        = "invalid_client"
+	ErrorInvalidGrant         = "invalid_grant"
+	ErrorUnauthorizedClient   = "unauthorized_client"
+	ErrorUnsupportedGrantType = "unsupported_grant_type"
+	ErrorInvalidScope         = "invalid_scope"
+	ErrorInvalidTarget        = "invalid_target" // RFC 8707 §2
+)
+
+// Error represents an OAuth 2.0 error response (RFC 6749 §4.1.2.1 and §5.2).
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	URI         string `json:"error_uri,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	message := "oauth2: " + e.Code
+	if e.StatusCode != 0 {
+		message = fmt.Sprintf("oauth2: %s (%d)", e.Code, e.StatusCode)
+	}
+	if e.Description != "" {
+		message += ": " + e.Description
+	}
+	return message
+}
@@ -0,0 +1,82 @@
+package grant
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/viant/mcp-protocol/oauth2/jws"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// Credentials holds the client authentication material available to a client.
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	// Key and KeyID enable private_key_jwt.
+	Key   crypto.Signer
+	KeyID string
+	// TLSClientCertificate reports that the HTTP client presents a client certificate, enabling tls_client_auth.
+	TLSClientCertificate bool
+	// Preferred lists authentication methods in order of preference; DefaultPreference when empty.
+	Preferred []string
+}
+
+// DefaultPreference orders authentication methods from strongest to weakest.
+var DefaultPreference = []string{AuthMethodPrivateKeyJWT, AuthMethodTLSClientAuth, AuthMethodClientSecretBasic, AuthMethodClientSecretPost, AuthMethodNone}
+
+// Negotiate selects the preferred authentication method supported by both the client credentials and
+// the authorization server (token_endpoint_auth_methods_supported; client_secret_basic when absent)
+// and returns its Authenticator with the selected method name.
+func Negotiate(server *meta.AuthorizationServerMetadata, credentials *Credentials) (Authenticator, string, error) {
+	if credentials == nil || credentials.ClientID == "" {
+		return nil, "", errors.New("client_id is required")
+	}
+	supported := []string{AuthMethodClientSecretBasic}
+	var signingAlgs []string
+	if server != nil {
+		if len(server.TokenEndpointAuthMethodsSupported) > 0 {
+			supported = server.TokenEndpointAuthMethodsSupported
+		}
+		signingAlgs = server.TokenEndpointAuthSigningAlgValuesSupported
+	}
+	preferred := credentials.Preferred
+	if len(preferred) == 0 {
+		preferred = DefaultPreference
+	}
+	for _, method := range preferred {
+		if !slices.Contains(supported, method) {
+			continue
+		}
+		switch method {
+		case AuthMethodPrivateKeyJWT:
+			if credentials.Key == nil {
+				continue
+			}
+			alg, err := jws.DefaultAlgorithm(credentials.Key)
+			if err != nil || (len(signingAlgs) > 0 && !slices.Contains(signingAlgs, alg)) {
+				continue
+			}
+			return &PrivateKeyJWT{ClientID: credentials.ClientID, Key: credentials.Key, KeyID: credentials.KeyID, Algorithm: alg}, method, nil
+		case AuthMethodTLSClientAuth:
+			if !credentials.TLSClientCertificate {
+				continue
+			}
+			return &TLSClientAuth{ClientID: credentials.ClientID}, method, nil
+		case AuthMethodClientSecretBasic:
+			if credentials.ClientSecret == "" {
+				continue
+			}
+			return &ClientSecretBasic{ClientID: credentials.ClientID, ClientSecret: credentials.ClientSecret}, method, nil
+		case AuthMethodClientSecretPost:
+			if credentials.ClientSecret == "" {
+				continue
+			}
+			return &ClientSecretPost{ClientID: credentials.ClientID, ClientSecret: credentials.ClientSecret}, method, nil
+		case AuthMethodNone:
+			return &None{ClientID: credentials.ClientID}, method, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no mutually supported token endpoint auth method (server supports %v)", supported)
+}
@@ -0,0 +1,68 @@
+package grant
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+func TestNegotiate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	testCases := []struct {
+		name        string
+		server      *meta.AuthorizationServerMetadata
+		credentials *Credentials
+		expected    string
+		expectErr   bool
+	}{
+		{
+			name:        "default client_secret_basic",
+			server:      &meta.AuthorizationServerMetadata{},
+			credentials: &Credentials{ClientID: "c", ClientSecret: "s"},
+			expected:    AuthMethodClientSecretBasic,
+		},
+		{
+			name:        "private_key_jwt preferred",
+			server:      &meta.AuthorizationServerMetadata{TokenEndpointAuthMethodsSupported: []string{AuthMethodClientSecretPost, AuthMethodPrivateKeyJWT}},
+			credentials: &Credentials{ClientID: "c", ClientSecret: "s", Key: key},
+			expected:    AuthMethodPrivateKeyJWT,
+		},
+		{
+			name: "private_key_jwt algorithm not supported",
+			server: &meta.AuthorizationServerMetadata{
+				TokenEndpointAuthMethodsSupported:          []string{AuthMethodClientSecretPost, AuthMethodPrivateKeyJWT},
+				TokenEndpointAuthSigningAlgValuesSupported: []string{"RS256"},
+			},
+			credentials: &Credentials{ClientID: "c", ClientSecret: "s", Key: key},
+			expected:    AuthMethodClientSecretPost,
+		},
+		{
+			name:        "tls_client_auth",
+			server:      &meta.AuthorizationServerMetadata{TokenEndpointAuthMethodsSupported: []string{AuthMethodTLSClientAuth}},
+			credentials: &Credentials{ClientID: "c", TLSClientCertificate: true},
+			expected:    AuthMethodTLSClientAuth,
+		},
+		{
+			name:        "no common method",
+			server:      &meta.AuthorizationServerMetadata{TokenEndpointAuthMethodsSupported: []string{AuthMethodPrivateKeyJWT}},
+			credentials: &Credentials{ClientID: "c", ClientSecret: "s"},
+			expectErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		_, method, err := Negotiate(tc.server, tc.credentials)
+		if tc.expectErr {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, method, tc.name)
+	}
+}
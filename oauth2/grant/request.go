@@ -0,0 +1,142 @@
+package grant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Grant type identifiers.
+const (
+	TypeAuthorizationCode = "authorization_code"
+	TypeRefreshToken      = "refresh_token"
+	TypeClientCredentials = "client_credentials"
+)
+
+// Request posts grant parameters to the token endpoint and returns the issued token.
+// Members of the token response not modelled by oauth2.Token are available via Token.Extra.
+func Request(ctx context.Context, client *http.Client, endpoint string, params url.Values, auth Authenticator) (*oauth2.Token, error) {
+	token, _, err := request(ctx, client, endpoint, params, auth)
+	return token, err
+}
+
+func request(ctx context.Context, client *http.Client, endpoint string, params url.Values, auth Authenticator) (*oauth2.Token, map[string]any, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if endpoint == "" {
+		return nil, nil, errors.New("token endpoint was empty")
+	}
+	header := http.Header{}
+	if auth != nil {
+		if err := auth.Authenticate(ctx, endpoint, header, params); err != nil {
+			return nil, nil, fmt.Errorf("failed to authenticate client: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	return decodeToken(resp, body)
+}
+
+func decodeToken(resp *http.Response, body []byte) (*oauth2.Token, map[string]any, error) {
+	var raw map[string]any
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch contentType {
+	case "application/x-www-form-urlencoded", "text/plain":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode token response: %w", err)
+		}
+		raw = make(map[string]any, len(values))
+		for k := range values {
+			raw[k] = values.Get(k)
+		}
+	default:
+		if err := json.Unmarshal(body, &raw); err != nil {
+			if resp.StatusCode != http.StatusOK {
+				return nil, nil, &Error{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode), Description: string(body)}
+			}
+			return nil, nil, fmt.Errorf("failed to decode token response: %w", err)
+		}
+	}
+	if code, _ := raw["error"].(string); code != "" || resp.StatusCode != http.StatusOK {
+		ret := &Error{StatusCode: resp.StatusCode, Code: code}
+		ret.Description, _ = raw["error_description"].(string)
+		ret.URI, _ = raw["error_uri"].(string)
+		if ret.Code == "" {
+			ret.Code = http.StatusText(resp.StatusCode)
+		}
+		return nil, nil, ret
+	}
+	token := &oauth2.Token{}
+	token.AccessToken, _ = raw["access_token"].(string)
+	token.TokenType, _ = raw["token_type"].(string)
+	token.RefreshToken, _ = raw["refresh_token"].(string)
+	if token.AccessToken == "" {
+		return nil, nil, errors.New("token response has no access_token")
+	}
+	switch expiresIn := raw["expires_in"].(type) {
+	case float64:
+		token.ExpiresIn = int64(expiresIn)
+	case string: // some servers encode expires_in as string
+		_, _ = fmt.Sscan(expiresIn, &token.ExpiresIn)
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return token.WithExtra(raw), raw, nil
+}
+
+// RefreshToken refreshes token at the token endpoint, preserving the refresh and ID tokens when the
+// server does not reissue them. Additional parameters (e.g. resource, scope) are sent as supplied.
+func RefreshToken(ctx context.Context, client *http.Client, endpoint string, token *oauth2.Token, params url.Values, auth Authenticator) (*oauth2.Token, error) {
+	if token == nil || token.RefreshToken == "" {
+		return nil, errors.New("token has no refresh_token")
+	}
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form.Set("grant_type", TypeRefreshToken)
+	form.Set("refresh_token", token.RefreshToken)
+	refreshed, raw, err := request(ctx, client, endpoint, form, auth)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+		raw["refresh_token"] = token.RefreshToken
+	}
+	if _, ok := raw["id_token"]; !ok {
+		if idToken, ok := token.Extra("id_token").(string); ok {
+			raw["id_token"] = idToken
+		}
+	}
+	return refreshed.WithExtra(raw), nil
+}
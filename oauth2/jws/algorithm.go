@@ -0,0 +1,94 @@
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+)
+
+// Algorithm identifiers (RFC 7518 §3.1, RFC 8037 §3.1).
+const (
+	RS256 = "RS256"
+	RS384 = "RS384"
+	RS512 = "RS512"
+	PS256 = "PS256"
+	PS384 = "PS384"
+	PS512 = "PS512"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	EdDSA = "EdDSA"
+)
+
+// Algorithms lists all supported signature algorithms.
+var Algorithms = []string{RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512, EdDSA}
+
+// DefaultAlgorithm returns the conventional algorithm for a public or private key.
+func DefaultAlgorithm(key any) (string, error) {
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return RS256, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return ES256, nil
+		case elliptic.P384():
+			return ES384, nil
+		case elliptic.P521():
+			return ES512, nil
+		}
+		return "", fmt.Errorf("unsupported EC curve %s", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return EdDSA, nil
+	}
+	return "", fmt.Errorf("unsupported key type %T", key)
+}
+
+// hashFor returns the digest used by alg.
+func hashFor(alg string) (crypto.Hash, error) {
+	switch alg {
+	case RS256, PS256, ES256:
+		return crypto.SHA256, nil
+	case RS384, PS384, ES384:
+		return crypto.SHA384, nil
+	case RS512, PS512, ES512:
+		return crypto.SHA512, nil
+	case EdDSA:
+		return crypto.Hash(0), nil
+	}
+	return 0, fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+// checkKey verifies that the public key matches the algorithm family.
+func checkKey(alg string, key crypto.PublicKey) error {
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		if k, ok := key.(*rsa.PublicKey); ok {
+			if k.N.BitLen() < 2048 {
+				return fmt.Errorf("RSA key size %d is below 2048 bits", k.N.BitLen())
+			}
+			return nil
+		}
+	case ES256, ES384, ES512:
+		if k, ok := key.(*ecdsa.PublicKey); ok {
+			expected, _ := DefaultAlgorithm(k)
+			if expected != alg {
+				return fmt.Errorf("algorithm %s does not match EC curve %s", alg, k.Curve.Params().Name)
+			}
+			return nil
+		}
+	case EdDSA:
+		if _, ok := key.(ed25519.PublicKey); ok {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	return fmt.Errorf("key type %T does not match algorithm %s", key, alg)
+}
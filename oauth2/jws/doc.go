@@ -0,0 +1,7 @@
+// Package jws implements the subset of JSON Web Signature (RFC 7515) compact
+// serialization needed by the OAuth 2.0 extensions in this module: DPoP
+// proofs, JWT client assertions and signed metadata.
+//
+// Supported algorithms are RS256/384/512, PS256/384/512, ES256/384/512 and
+// EdDSA (Ed25519). The "none" algorithm is never accepted.
+package jws
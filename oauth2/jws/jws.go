@@ -0,0 +1,178 @@
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// Header represents the JOSE header of a JWS.
+type Header struct {
+	Algorithm string           `json:"alg"`
+	Type      string           `json:"typ,omitempty"`
+	KeyID     string           `json:"kid,omitempty"`
+	JWK       *meta.JSONWebKey `json:"jwk,omitempty"`
+	X5c       []string         `json:"x5c,omitempty"`
+}
+
+// JWS is a parsed compact-serialized JSON Web Signature.
+type JWS struct {
+	Header    Header
+	Payload   []byte
+	signing   string
+	signature []byte
+}
+
+// Claims decodes the payload into v.
+func (j *JWS) Claims(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Verify verifies the signature with key, which must match the header algorithm.
+func (j *JWS) Verify(key crypto.PublicKey) error {
+	alg := j.Header.Algorithm
+	if err := checkKey(alg, key); err != nil {
+		return err
+	}
+	hash, err := hashFor(alg)
+	if err != nil {
+		return err
+	}
+	digest := []byte(j.signing)
+	if hash != 0 {
+		h := hash.New()
+		h.Write(digest)
+		digest = h.Sum(nil)
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "PS") {
+			err = rsa.VerifyPSS(k, hash, digest, j.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(k, hash, digest, j.signature)
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(j.signature) != 2*size {
+			return errors.New("invalid ECDSA signature length")
+		}
+		r := new(big.Int).SetBytes(j.signature[:size])
+		s := new(big.Int).SetBytes(j.signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			err = errors.New("ECDSA verification failed")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, digest, j.signature) {
+			err = errors.New("Ed25519 verification failed")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// Parse parses a compact-serialized JWS without verifying its signature.
+func Parse(token string) (*JWS, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed JWS: expected 3 segments")
+	}
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWS header: %w", err)
+	}
+	ret := &JWS{signing: parts[0] + "." + parts[1]}
+	if err := json.Unmarshal(headerData, &ret.Header); err != nil {
+		return nil, fmt.Errorf("malformed JWS header: %w", err)
+	}
+	if ret.Header.Algorithm == "" || strings.EqualFold(ret.Header.Algorithm, "none") {
+		return nil, fmt.Errorf("unsupported JWS algorithm %q", ret.Header.Algorithm)
+	}
+	if ret.Payload, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+		return nil, fmt.Errorf("malformed JWS payload: %w", err)
+	}
+	if ret.signature, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("malformed JWS signature: %w", err)
+	}
+	return ret, nil
+}
+
+// Sign serializes claims as the payload and signs it with key. When header.Algorithm is empty the
+// key's DefaultAlgorithm is used.
+func Sign(header Header, claims any, key crypto.Signer) (string, error) {
+	if header.Algorithm == "" {
+		alg, err := DefaultAlgorithm(key)
+		if err != nil {
+			return "", err
+		}
+		header.Algorithm = alg
+	}
+	if err := checkKey(header.Algorithm, key.Public()); err != nil {
+		return "", err
+	}
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signing := base64.RawURLEncoding.EncodeToString(headerData) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := sign(header.Algorithm, signing, key)
+	if err != nil {
+		return "", err
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func sign(alg, signing string, key crypto.Signer) ([]byte, error) {
+	hash, err := hashFor(alg)
+	if err != nil {
+		return nil, err
+	}
+	digest := []byte(signing)
+	if hash != 0 {
+		h := hash.New()
+		h.Write(digest)
+		digest = h.Sum(nil)
+	}
+	var opts crypto.SignerOpts = hash
+	if strings.HasPrefix(alg, "PS") {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+	signature, err := key.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	if pub, ok := key.Public().(*ecdsa.PublicKey); ok {
+		return ecdsaRaw(signature, (pub.Curve.Params().BitSize+7)/8)
+	}
+	return signature, nil
+}
+
+// ecdsaRaw converts an ASN.1 DER ECDSA signature into the fixed-size R||S form required by RFC 7518 §3.4.
+func ecdsaRaw(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("invalid ECDSA signature: %w", err)
+	}
+	ret := make([]byte, 2*size)
+	sig.R.FillBytes(ret[:size])
+	sig.S.FillBytes(ret[size:])
+	return ret, nil
+}
@@ -0,0 +1,93 @@
+package jws
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+var (
+	_ meta.Signer   = (*MetadataSigner)(nil)
+	_ meta.Verifier = (*MetadataVerifier)(nil)
+)
+
+// MetadataSigner creates signed_metadata JWTs (RFC 8414 §2.1, RFC 9728 §2.2).
+type MetadataSigner struct {
+	// Issuer is the "iss" claim identifying the party attesting to the metadata. When empty, the issuer
+	// or resource member of the signed document is used, as required by VerifySignedMetadata.
+	Issuer    string
+	KeyID     string
+	Algorithm string
+	Key       crypto.Signer
+}
+
+// SignMetadata signs claims, adding the iss and iat claims.
+func (s *MetadataSigner) SignMetadata(claims map[string]any) (string, error) {
+	issuer := s.Issuer
+	if issuer == "" {
+		issuer, _ = claims["issuer"].(string)
+	}
+	if issuer == "" {
+		issuer, _ = claims["resource"].(string)
+	}
+	if issuer == "" {
+		return "", errors.New("signed metadata issuer was empty")
+	}
+	payload := make(map[string]any, len(claims)+2)
+	for k, v := range claims {
+		payload[k] = v
+	}
+	payload["iss"] = issuer
+	payload["iat"] = time.Now().Unix()
+	return Sign(Header{Algorithm: s.Algorithm, Type: "JWT", KeyID: s.KeyID}, payload, s.Key)
+}
+
+// MetadataVerifier verifies signed_metadata JWTs issued by a trusted party.
+type MetadataVerifier struct {
+	// Issuer is the expected "iss" claim.
+	Issuer string
+	// Keys maps key IDs to verification keys, as returned by meta.FetchJSONWebKeySet.
+	Keys map[string]crypto.PublicKey
+	// Algorithms lists accepted algorithms; all supported algorithms are accepted when empty.
+	Algorithms []string
+}
+
+// VerifyMetadata verifies the signature and issuer of signed and returns its claims.
+func (v *MetadataVerifier) VerifyMetadata(signed string) (map[string]any, error) {
+	token, err := Parse(signed)
+	if err != nil {
+		return nil, err
+	}
+	algorithms := v.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = Algorithms
+	}
+	if !slices.Contains(algorithms, token.Header.Algorithm) {
+		return nil, fmt.Errorf("unsupported algorithm %q", token.Header.Algorithm)
+	}
+	key, ok := v.Keys[token.Header.KeyID]
+	if !ok && token.Header.KeyID == "" && len(v.Keys) == 1 {
+		// a single trusted key may be used without a key ID
+		for _, candidate := range v.Keys {
+			key, ok = candidate, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", token.Header.KeyID)
+	}
+	if err := token.Verify(key); err != nil {
+		return nil, err
+	}
+	var claims map[string]any
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss == "" || (v.Issuer != "" && iss != v.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+	return claims, nil
+}
@@ -3,9 +3,11 @@ package meta
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 )
 
@@ -62,49 +64,136 @@ type AuthorizationServerMetadata struct {
 	Extra map[string]any `json:"-"`
 }
 
-// FetchAuthorizationServerMetadata fetches the Authorization Server
+// ErrIssuerMismatch is returned when the discovered metadata "issuer" differs from the requested issuer (RFC 8414 §3.3).
+var ErrIssuerMismatch = errors.New("authorization server metadata issuer mismatch")
+
+// FetchAuthorizationServerMetadata fetches the Authorization Server metadata.
+//
+// Discovery endpoints are tried in the order mandated by the MCP authorization specification:
+// RFC 8414 path-insertion form, OpenID Connect discovery with path insertion, then OpenID Connect
+// discovery with path appending; the legacy RFC 8414 path-appending form is tried last for
+// backward compatibility. The first document returned with status 200 is used and its issuer
+// must be identical to the requested issuer; candidates failing with another status, a transport
+// or a decoding error are skipped.
 func FetchAuthorizationServerMetadata(ctx context.Context, issuer string, client *http.Client) (*AuthorizationServerMetadata, error) {
 	if client == nil {
 		client = http.DefaultClient
 	}
 
-	wellKnownURL, err := joinWellKnown(issuer)
+	candidates, err := discoveryURLs(issuer)
 	if err != nil {
 		return nil, err
 	}
 
+	var errs []error
+	for _, candidate := range candidates {
+		metaDoc, status, err := fetchAuthorizationServerMetadata(ctx, candidate, client)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			// an unreachable or malformed candidate does not prevent discovery at the remaining ones
+			errs = append(errs, err)
+			continue
+		}
+		if status != http.StatusOK {
+			errs = append(errs, fmt.Errorf("%s: unexpected status code %d", candidate, status))
+			continue
+		}
+		if metaDoc.Issuer != issuer {
+			return nil, fmt.Errorf("%w: expected %q, got %q (%s)", ErrIssuerMismatch, issuer, metaDoc.Issuer, candidate)
+		}
+		return metaDoc, nil
+	}
+	return nil, fmt.Errorf("failed to discover authorization server metadata for %s: %w", issuer, errors.Join(errs...))
+}
+
+// fetchAuthorizationServerMetadata fetches a single discovery document, returning the HTTP status when it is not 200.
+func fetchAuthorizationServerMetadata(ctx context.Context, wellKnownURL string, client *http.Client) (*AuthorizationServerMetadata, int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnownURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, resp.StatusCode, nil
 	}
 
 	var metaDoc AuthorizationServerMetadata
 	if err := json.NewDecoder(resp.Body).Decode(&metaDoc); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode %s: %w", wellKnownURL, err)
+	}
+	return &metaDoc, resp.StatusCode, nil
+}
+
+// UnmarshalJSON custom unmarshal to preserve unknown members in Extra.
+func (m *AuthorizationServerMetadata) UnmarshalJSON(data []byte) error {
+	type alias AuthorizationServerMetadata
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*m = AuthorizationServerMetadata(a)
+	extra, err := ExtractExtra(data, reflect.TypeOf(a))
+	if err != nil {
+		return err
+	}
+	m.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes Extra back out.
+func (m AuthorizationServerMetadata) MarshalJSON() ([]byte, error) {
+	type alias AuthorizationServerMetadata
+	core, err := json.Marshal(alias(m))
+	if err != nil {
 		return nil, err
 	}
-	return &metaDoc, nil
+	return MergeExtra(core, m.Extra)
 }
 
-// joinWellKnown builds “…/.well-known/oauth-authorization-server” as  specified in RFC 8414 §5, preserving any existing issuer path and guaranteeing exactly one “/” separator.
-func joinWellKnown(issuer string) (string, error) {
+const (
+	oauthAuthorizationServerSuffix = "/.well-known/oauth-authorization-server"
+	openIDConfigurationSuffix      = "/.well-known/openid-configuration"
+)
+
+// discoveryURLs returns metadata discovery URLs for the issuer in priority order.
+func discoveryURLs(issuer string) ([]string, error) {
 	u, err := url.Parse(issuer)
 	if err != nil {
-		return "", fmt.Errorf("issuer URL parse error: %w", err)
+		return nil, fmt.Errorf("issuer URL parse error: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("issuer URL %q is not absolute", issuer)
 	}
-	// The well-known segment must be relative to the *issuer path* (if any).
-	u.Path = strings.TrimRight(u.Path, "/") + "/.well-known/oauth-authorization-server"
 	// Queries or fragments are not allowed on the discovery URL.
 	u.RawQuery = ""
 	u.Fragment = ""
-	return u.String(), nil
+	issuerPath := strings.TrimRight(u.Path, "/")
+
+	withPath := func(path string) string {
+		c := *u
+		c.Path = path
+		c.RawPath = ""
+		return c.String()
+	}
+	if issuerPath == "" {
+		return []string{
+			withPath(oauthAuthorizationServerSuffix),
+			withPath(openIDConfigurationSuffix),
+		}, nil
+	}
+	return []string{
+		withPath(oauthAuthorizationServerSuffix + issuerPath), // RFC 8414 §3.1
+		withPath(openIDConfigurationSuffix + issuerPath),      // RFC 8414 §5 compatibility
+		withPath(issuerPath + openIDConfigurationSuffix),      // OpenID Connect Discovery 1.0 §4
+		withPath(issuerPath + oauthAuthorizationServerSuffix), // legacy path appending
+	}, nil
 }
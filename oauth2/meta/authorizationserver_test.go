@@ -0,0 +1,80 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAuthorizationServerMetadata(t *testing.T) {
+	testCases := []struct {
+		name        string
+		issuerPath  string
+		servedPath  string
+		invalidPath string
+		issuerValue func(issuer string) string
+		expectErr   error
+	}{
+		{name: "rfc8414 no path", servedPath: "/.well-known/oauth-authorization-server"},
+		{name: "oidc no path", servedPath: "/.well-known/openid-configuration"},
+		{name: "rfc8414 path insertion", issuerPath: "/tenant1", servedPath: "/.well-known/oauth-authorization-server/tenant1"},
+		{name: "oidc path insertion", issuerPath: "/tenant1", servedPath: "/.well-known/openid-configuration/tenant1"},
+		{name: "oidc path appending", issuerPath: "/tenant1", servedPath: "/tenant1/.well-known/openid-configuration"},
+		{name: "malformed candidate skipped", issuerPath: "/tenant1", invalidPath: "/.well-known/oauth-authorization-server/tenant1", servedPath: "/.well-known/openid-configuration/tenant1"},
+		{
+			name:        "issuer mismatch",
+			servedPath:  "/.well-known/oauth-authorization-server",
+			issuerValue: func(issuer string) string { return issuer + "/other" },
+			expectErr:   ErrIssuerMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		var issuer string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == tc.invalidPath {
+				_, _ = w.Write([]byte("<html>"))
+				return
+			}
+			if r.URL.Path != tc.servedPath {
+				http.NotFound(w, r)
+				return
+			}
+			value := issuer
+			if tc.issuerValue != nil {
+				value = tc.issuerValue(issuer)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"issuer": value, "token_endpoint": issuer + "/token", "x_custom": "abc"})
+		}))
+		issuer = srv.URL + tc.issuerPath
+		metaDoc, err := FetchAuthorizationServerMetadata(context.Background(), issuer, srv.Client())
+		srv.Close()
+		if tc.expectErr != nil {
+			assert.True(t, errors.Is(err, tc.expectErr), tc.name)
+			continue
+		}
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		assert.Equal(t, issuer, metaDoc.Issuer, tc.name)
+		assert.Equal(t, issuer+"/token", metaDoc.TokenEndpoint, tc.name)
+		assert.Equal(t, "abc", metaDoc.Extra["x_custom"], tc.name)
+	}
+}
+
+func TestProtectedResourceMetadata_Extra(t *testing.T) {
+	input := `{"resource":"https://mcp.example.com","authorization_servers":["https://as.example.com"],"x_tenant":{"id":9007199254740993}}`
+	var metaDoc ProtectedResourceMetadata
+	assert.NoError(t, json.Unmarshal([]byte(input), &metaDoc))
+	assert.Equal(t, map[string]any{"id": json.Number("9007199254740993")}, metaDoc.Extra["x_tenant"])
+	assert.NotContains(t, metaDoc.Extra, "resource")
+
+	output, err := json.Marshal(metaDoc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, input, string(output))
+}
@@ -5,5 +5,7 @@
 // The helpers exposed by the package make it straightforward to fetch and
 // parse those metadata documents as part of an MCP server or client – for
 // instance to discover token endpoints, download signing keys or advertise
-// public‐facing capabilities.
+// public‐facing capabilities. NewProtectedResourceHandler and
+// NewAuthorizationServerHandler serve those documents at their well-known
+// paths, optionally with a signed_metadata JWT.
 package meta
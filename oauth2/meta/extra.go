@@ -0,0 +1,64 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// knownMembers returns the JSON member names declared by struct types.
+func knownMembers(types ...reflect.Type) map[string]bool {
+	result := map[string]bool{}
+	for _, t := range types {
+		for i := 0; i < t.NumField(); i++ {
+			key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if key == "" || key == "-" {
+				continue
+			}
+			result[key] = true
+		}
+	}
+	return result
+}
+
+// ExtractExtra returns all members of the JSON object that are not declared by any of the struct types,
+// for capturing unknown members in an Extra map. Numbers are kept as json.Number, so that they
+// are written back unchanged.
+func ExtractExtra(data []byte, types ...reflect.Type) (map[string]any, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := knownMembers(types...)
+	extra := make(map[string]any, len(raw))
+	for k, v := range raw {
+		if known[k] {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(v))
+		decoder.UseNumber()
+		var vAny any
+		_ = decoder.Decode(&vAny)
+		extra[k] = vAny
+	}
+	return extra, nil
+}
+
+// MergeExtra adds extra members to an already marshalled JSON object; declared members take precedence.
+func MergeExtra(core []byte, extra map[string]any) ([]byte, error) {
+	if len(extra) == 0 {
+		return core, nil
+	}
+	var base map[string]any
+	if err := json.Unmarshal(core, &base); err != nil {
+		return nil, err
+	}
+	for k, v := range extra {
+		if _, ok := base[k]; ok {
+			continue
+		}
+		base[k] = v
+	}
+	return json.Marshal(base)
+}
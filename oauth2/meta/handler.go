@@ -0,0 +1,205 @@
+package meta
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxAge is the default Cache-Control max-age of served metadata.
+const DefaultMaxAge = time.Hour
+
+// Handler serves metadata documents at their well-known paths with caching and CORS headers.
+//
+// Register it for every path returned by Paths, or for the well-known prefixes, e.g.
+//
+//	mux.Handle(meta.ProtectedResourceMetadataSuffix, handler)
+//	mux.Handle(meta.ProtectedResourceMetadataSuffix+"/", handler)
+type Handler struct {
+	documents map[string]*document
+	maxAge    time.Duration
+	origins   []string
+	signer    Signer
+}
+
+// document is a pre-encoded metadata document.
+type document struct {
+	source []byte
+	body   []byte
+	etag   string
+}
+
+// HandlerOption customizes a Handler.
+type HandlerOption func(h *Handler)
+
+// WithMaxAge sets the Cache-Control max-age; zero disables caching.
+func WithMaxAge(maxAge time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.maxAge = maxAge
+	}
+}
+
+// WithAllowedOrigins restricts CORS to the given origins; by default any origin may read metadata.
+func WithAllowedOrigins(origins ...string) HandlerOption {
+	return func(h *Handler) {
+		h.origins = origins
+	}
+}
+
+// WithSigner adds a signed_metadata member created by signer to every served document.
+func WithSigner(signer Signer) HandlerOption {
+	return func(h *Handler) {
+		h.signer = signer
+	}
+}
+
+// ServeHTTP serves the document registered for the request path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	doc, ok := h.documents[strings.TrimRight(r.URL.Path, "/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h.setCORS(w, r)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.Header().Set("Access-Control-Max-Age", "86400")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if h.maxAge > 0 {
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.maxAge.Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Header().Set("ETag", doc.etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && (match == doc.etag || match == "*") {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(doc.body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(doc.body)
+	}
+}
+
+// Paths returns the request paths served by the handler.
+func (h *Handler) Paths() []string {
+	ret := make([]string, 0, len(h.documents))
+	for path := range h.documents {
+		ret = append(ret, path)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (h *Handler) setCORS(w http.ResponseWriter, r *http.Request) {
+	if len(h.origins) == 0 {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	for _, candidate := range h.origins {
+		if candidate == origin || candidate == "*" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			return
+		}
+	}
+}
+
+// add encodes and registers a document under path; differing documents at the same path are rejected.
+func (h *Handler) add(path string, metadata any) error {
+	source, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	path = strings.TrimRight(path, "/")
+	if existing, ok := h.documents[path]; ok {
+		if string(existing.source) != string(source) {
+			return fmt.Errorf("conflicting metadata documents for %s", path)
+		}
+		return nil
+	}
+	body := source
+	if h.signer != nil {
+		signed, err := signMetadata(metadata, h.signer)
+		if err != nil {
+			return err
+		}
+		if body, err = json.Marshal(signed); err != nil {
+			return fmt.Errorf("failed to encode metadata: %w", err)
+		}
+	}
+	digest := sha256.Sum256(body)
+	h.documents[path] = &document{source: source, body: body, etag: `"` + base64.RawURLEncoding.EncodeToString(digest[:16]) + `"`}
+	return nil
+}
+
+// NewProtectedResourceHandler creates a Handler serving protected resource metadata (RFC 9728 §3).
+// Each document is served at the well-known path derived from its resource identifier, so resources
+// with a path such as https://mcp.example.com/tools/search are served at
+// /.well-known/oauth-protected-resource/tools/search.
+func NewProtectedResourceHandler(resources []*ProtectedResourceMetadata, options ...HandlerOption) (*Handler, error) {
+	ret := newHandler(options)
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		metadataURL, err := ProtectedResourceMetadataURL(resource.Resource)
+		if err != nil {
+			return nil, err
+		}
+		u, _ := url.Parse(metadataURL)
+		if err := ret.add(u.Path, resource); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// NewAuthorizationServerHandler creates a Handler serving authorization server metadata at the
+// RFC 8414 and OpenID Connect discovery paths derived from the issuer.
+func NewAuthorizationServerHandler(server *AuthorizationServerMetadata, options ...HandlerOption) (*Handler, error) {
+	if server == nil {
+		return nil, fmt.Errorf("authorization server metadata was nil")
+	}
+	candidates, err := discoveryURLs(server.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	ret := newHandler(options)
+	for _, candidate := range candidates {
+		u, _ := url.Parse(candidate)
+		if err := ret.add(u.Path, server); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func newHandler(options []HandlerOption) *Handler {
+	ret := &Handler{documents: map[string]*document{}, maxAge: DefaultMaxAge}
+	for _, option := range options {
+		option(ret)
+	}
+	return ret
+}
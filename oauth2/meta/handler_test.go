@@ -0,0 +1,153 @@
+package meta
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSigner encodes claims without a signature, adding issuer as the iss claim.
+type testSigner struct {
+	issuer string
+}
+
+func (s testSigner) SignMetadata(claims map[string]any) (string, error) {
+	payload := map[string]any{"iss": s.issuer}
+	for k, v := range claims {
+		payload[k] = v
+	}
+	data, err := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data), err
+}
+
+func (testSigner) VerifyMetadata(signed string) (map[string]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(signed)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]any
+	return claims, json.Unmarshal(data, &claims)
+}
+
+func TestNewProtectedResourceHandler(t *testing.T) {
+	handler, err := NewProtectedResourceHandler([]*ProtectedResourceMetadata{
+		{Resource: "https://mcp.example.com", AuthorizationServers: []string{"https://as.example.com"}},
+		{Resource: "https://mcp.example.com/tools/search", ScopesSupported: []string{"search"}},
+	}, WithAllowedOrigins("https://app.example.com"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{ProtectedResourceMetadataSuffix, ProtectedResourceMetadataSuffix + "/tools/search"}, handler.Paths())
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		header       map[string]string
+		expectStatus int
+		expectHeader map[string]string
+		expectBody   string
+	}{
+		{
+			name:         "root",
+			method:       http.MethodGet,
+			path:         ProtectedResourceMetadataSuffix,
+			header:       map[string]string{"Origin": "https://app.example.com"},
+			expectStatus: http.StatusOK,
+			expectHeader: map[string]string{"Content-Type": "application/json", "Cache-Control": "public, max-age=3600", "Access-Control-Allow-Origin": "https://app.example.com"},
+			expectBody:   `{"resource":"https://mcp.example.com","authorization_servers":["https://as.example.com"]}`,
+		},
+		{
+			name:         "per tool",
+			method:       http.MethodGet,
+			path:         ProtectedResourceMetadataSuffix + "/tools/search",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"resource":"https://mcp.example.com/tools/search","scopes_supported":["search"]}`,
+		},
+		{
+			name:         "preflight",
+			method:       http.MethodOptions,
+			path:         ProtectedResourceMetadataSuffix,
+			header:       map[string]string{"Origin": "https://app.example.com"},
+			expectStatus: http.StatusNoContent,
+			expectHeader: map[string]string{"Access-Control-Allow-Methods": "GET, HEAD, OPTIONS"},
+		},
+		{name: "unknown path", method: http.MethodGet, path: ProtectedResourceMetadataSuffix + "/other", expectStatus: http.StatusNotFound},
+		{name: "method", method: http.MethodPost, path: ProtectedResourceMetadataSuffix, expectStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		for k, v := range tc.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tc.expectStatus, rec.Code, tc.name)
+		for k, v := range tc.expectHeader {
+			assert.Equal(t, v, rec.Header().Get(k), tc.name)
+		}
+		if tc.expectBody != "" {
+			assert.JSONEq(t, tc.expectBody, rec.Body.String(), tc.name)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, ProtectedResourceMetadataSuffix, nil)
+	req.Header.Set("If-None-Match", handler.documents[ProtectedResourceMetadataSuffix].etag)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestSignedMetadata(t *testing.T) {
+	handler, err := NewProtectedResourceHandler([]*ProtectedResourceMetadata{{Resource: "https://mcp.example.com", ScopesSupported: []string{"read"}}}, WithSigner(testSigner{issuer: "https://mcp.example.com"}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ProtectedResourceMetadataSuffix, nil))
+
+	var metadata ProtectedResourceMetadata
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metadata))
+	assert.NotEmpty(t, metadata.Extra[SignedMetadataMember])
+
+	// signed values take precedence over tampered plain members
+	metadata.ScopesSupported = []string{"admin"}
+	assert.NoError(t, metadata.VerifySignedMetadata(testSigner{}))
+	assert.Equal(t, []string{"read"}, metadata.ScopesSupported)
+
+	assert.ErrorIs(t, (&ProtectedResourceMetadata{}).VerifySignedMetadata(testSigner{}), ErrNoSignedMetadata)
+
+	// metadata attested for one resource must not be accepted for another
+	replayed := metadata
+	replayed.Resource = "https://other.example.com"
+	assert.ErrorIs(t, replayed.VerifySignedMetadata(testSigner{}), ErrSignedMetadataIssuer)
+}
+
+func TestSignedMetadata_Issuer(t *testing.T) {
+	sign := func(claims map[string]any) map[string]any {
+		signed, _ := testSigner{}.SignMetadata(claims)
+		return map[string]any{SignedMetadataMember: signed}
+	}
+	testCases := []struct {
+		name      string
+		metadata  *AuthorizationServerMetadata
+		expectErr error
+	}{
+		{name: "valid", metadata: &AuthorizationServerMetadata{Issuer: "https://as.example.com", Extra: sign(map[string]any{"iss": "https://as.example.com", "issuer": "https://as.example.com"})}},
+		{name: "missing iss", metadata: &AuthorizationServerMetadata{Issuer: "https://as.example.com", Extra: sign(map[string]any{"issuer": "https://as.example.com"})}, expectErr: ErrSignedMetadataIssuer},
+		{name: "other iss", metadata: &AuthorizationServerMetadata{Issuer: "https://as.example.com", Extra: sign(map[string]any{"iss": "https://evil.example.com"})}, expectErr: ErrSignedMetadataIssuer},
+		{name: "other signed issuer", metadata: &AuthorizationServerMetadata{Issuer: "https://as.example.com", Extra: sign(map[string]any{"iss": "https://as.example.com", "issuer": "https://evil.example.com"})}, expectErr: ErrSignedMetadataIssuer},
+	}
+	for _, tc := range testCases {
+		err := tc.metadata.VerifySignedMetadata(testSigner{})
+		if tc.expectErr != nil {
+			assert.ErrorIs(t, err, tc.expectErr, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+}
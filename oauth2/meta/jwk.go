@@ -9,11 +9,11 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"reflect"
-	"strings"
 )
 
 // JSONWebKey represents one JSON Web Key.
@@ -49,7 +49,7 @@ type JSONWebKey struct {
 
 	// ----- X.509 certificate chain / thumbprints -----
 	X5u     string   `json:"x5u,omitempty"`      // URL for cert set
-	X5c     []string `json:"x5c,omitempty"`      // PEM-encoded cert chain
+	X5c     []string `json:"x5c,omitempty"`      // base64 DER-encoded cert chain
 	X5t     string   `json:"x5t,omitempty"`      // SHA-1 thumbprint
 	X5tS256 string   `json:"x5t#S256,omitempty"` // SHA-256 thumbprint
 
@@ -85,55 +85,65 @@ func FetchJSONWebKeySet(ctx context.Context, jwksURL string, client *http.Client
 	}
 
 	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
-
 	for _, k := range jwks.Keys {
-		switch k.Kty {
-
-		case "RSA":
-			pub, err := parseRSAPublicKey(k.N, k.E)
-			if err != nil {
-				return nil, fmt.Errorf("kid=%s: %w", k.Kid, err)
+		pub, err := k.PublicKey()
+		if err != nil {
+			if errors.Is(err, errUnsupportedKeyType) {
+				continue // silently ignore unsupported kty values
 			}
-			keys[k.Kid] = pub
+			return nil, fmt.Errorf("kid=%s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
 
-		case "EC":
-			curve, err := curveForName(k.Crv)
-			if err != nil {
-				return nil, fmt.Errorf("kid=%s: %w", k.Kid, err)
-			}
-			xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
-			if err != nil {
-				return nil, fmt.Errorf("kid=%s: decode x: %w", k.Kid, err)
-			}
-			yBytes, err := base64.RawURLEncoding.DecodeString(k.Y)
-			if err != nil {
-				return nil, fmt.Errorf("kid=%s: decode y: %w", k.Kid, err)
-			}
-			pub := &ecdsa.PublicKey{
-				Curve: curve,
-				X:     new(big.Int).SetBytes(xBytes),
-				Y:     new(big.Int).SetBytes(yBytes),
-			}
-			keys[k.Kid] = pub
+var errUnsupportedKeyType = errors.New("unsupported key type")
 
-		case "OKP": // RFC 8037 (Ed25519 / Ed448, X25519 / X448 for DH)
-			if k.Crv != "Ed25519" {
-				return nil, fmt.Errorf("kid=%s: unsupported OKP curve %q", k.Kid, k.Crv)
-			}
-			xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
-			if err != nil {
-				return nil, fmt.Errorf("kid=%s: decode x: %w", k.Kid, err)
-			}
-			if l := len(xBytes); l != ed25519.PublicKeySize {
-				return nil, fmt.Errorf("kid=%s: Ed25519 key length %d != %d", k.Kid, l, ed25519.PublicKeySize)
-			}
-			keys[k.Kid] = ed25519.PublicKey(xBytes)
+// PublicKey converts the key to a crypto.PublicKey.  Supports RSA, EC (P-256 / P-384 / P-521) and OKP (Ed25519) keys.
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+
+	case "RSA":
+		return parseRSAPublicKey(k.N, k.E)
 
-		default:
-			// silently ignore unsupported kty values or return error if preferred
+	case "EC":
+		curve, err := curveForName(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
 		}
+		yBytes, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(xBytes),
+			Y:     new(big.Int).SetBytes(yBytes),
+		}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("EC point is not on curve %s", k.Crv)
+		}
+		return pub, nil
+
+	case "OKP": // RFC 8037 (Ed25519 / Ed448, X25519 / X448 for DH)
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", k.Crv)
+		}
+		xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		if l := len(xBytes); l != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Ed25519 key length %d != %d", l, ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKey(xBytes), nil
 	}
-	return keys, nil
+	return nil, fmt.Errorf("%w %q", errUnsupportedKeyType, k.Kty)
 }
 
 // parseRSAPublicKey creates an RSA public key from modulus and exponent
@@ -177,23 +187,11 @@ func (j *JSONWebKey) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	*j = JSONWebKey(a)
-
-	// Capture extensions
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(data, &raw); err != nil {
+	extra, err := ExtractExtra(data, reflect.TypeOf(a))
+	if err != nil {
 		return err
 	}
-	known := reflect.TypeOf(a)
-	for i := 0; i < known.NumField(); i++ {
-		key := strings.Split(known.Field(i).Tag.Get("json"), ",")[0]
-		delete(raw, key)
-	}
-	j.Extra = make(map[string]any, len(raw))
-	for k, v := range raw {
-		var vAny any
-		_ = json.Unmarshal(v, &vAny)
-		j.Extra[k] = vAny
-	}
+	j.Extra = extra
 	return nil
 }
 
@@ -204,13 +202,5 @@ func (j JSONWebKey) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(j.Extra) == 0 {
-		return core, nil
-	}
-	var base map[string]any
-	_ = json.Unmarshal(core, &base)
-	for k, v := range j.Extra {
-		base[k] = v
-	}
-	return json.Marshal(base)
+	return MergeExtra(core, j.Extra)
 }
@@ -0,0 +1,78 @@
+package meta
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// NewJSONWebKey converts an RSA, EC or Ed25519 public key to its JSON Web Key representation.
+// A crypto.Signer is accepted in place of its public key; private material is never encoded.
+func NewJSONWebKey(key any) (*JSONWebKey, error) {
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return &JSONWebKey{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		// coordinates are padded to the curve size (RFC 7518 §6.2.1.2)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		k.X.FillBytes(x)
+		k.Y.FillBytes(y)
+		return &JSONWebKey{
+			Kty: "EC",
+			Crv: k.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(x),
+			Y:   base64.RawURLEncoding.EncodeToString(y),
+		}, nil
+	case ed25519.PublicKey:
+		return &JSONWebKey{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(k)}, nil
+	}
+	return nil, fmt.Errorf("%w %T", errUnsupportedKeyType, key)
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the key, as used by cnf.jkt and as a key ID.
+func (k *JSONWebKey) Thumbprint() (string, error) {
+	var members map[string]string
+	switch k.Kty {
+	case "RSA":
+		members = map[string]string{"e": k.E, "kty": k.Kty, "n": k.N}
+	case "EC":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y}
+	case "OKP":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X}
+	case "oct":
+		members = map[string]string{"k": k.K, "kty": k.Kty}
+	default:
+		return "", fmt.Errorf("%w %q", errUnsupportedKeyType, k.Kty)
+	}
+	// encoding/json sorts map keys, producing the lexicographic order required by RFC 7638 §3.3
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// Key returns the key with the given key ID, or nil.
+func (s *JSONWebKeySet) Key(kid string) *JSONWebKey {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
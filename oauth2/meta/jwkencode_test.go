@@ -0,0 +1,134 @@
+package meta
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONWebKey_Thumbprint(t *testing.T) {
+	// RFC 7638 §3.1 example
+	input := `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+	key := &JSONWebKey{}
+	assert.NoError(t, json.Unmarshal([]byte(input), key))
+	actual, err := key.Thumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", actual)
+}
+
+func TestNewJSONWebKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	for _, key := range []any{rsaKey, &ecKey.PublicKey, edKey} {
+		jwk, err := NewJSONWebKey(key)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		public, err := jwk.PublicKey()
+		assert.NoError(t, err)
+		roundTrip, _ := NewJSONWebKey(public)
+		assert.Equal(t, jwk, roundTrip)
+	}
+	_, err := NewJSONWebKey("not a key")
+	assert.Error(t, err)
+}
+
+func TestJSONWebKey_VerifyCertificates(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	ca, _ := x509.ParseCertificate(caDER)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	leafDER, _ := x509.CreateCertificate(rand.Reader, leafTemplate, ca, leafKey.Public(), caKey)
+	leaf, _ := x509.ParseCertificate(leafDER)
+
+	jwk, err := NewJSONWebKeyFromCertificates(leaf, ca)
+	if !assert.NoError(t, err) {
+		return
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	chains, err := jwk.VerifyCertificates(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	assert.NoError(t, err)
+	assert.Len(t, chains, 1)
+
+	_, err = jwk.VerifyCertificates(x509.VerifyOptions{Roots: x509.NewCertPool()})
+	assert.Error(t, err)
+
+	other, _ := NewJSONWebKey(caKey.Public())
+	other.X5c = jwk.X5c
+	_, err = other.VerifyCertificates(x509.VerifyOptions{Roots: roots})
+	assert.Error(t, err)
+}
+
+func TestKeyManager(t *testing.T) {
+	manager, err := NewKeyManager(WithGracePeriod(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	first, _ := manager.Current()
+	second, err := manager.Rotate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.KeyID, second.KeyID)
+
+	keySet := manager.KeySet()
+	assert.Len(t, keySet.Keys, 2)
+	assert.Equal(t, second.KeyID, keySet.Keys[0].Kid)
+	assert.NotNil(t, keySet.Key(first.KeyID))
+	assert.Contains(t, manager.PublicKeys(), first.KeyID)
+
+	// rotating to the current key publishes no duplicate
+	same, err := manager.RotateTo(second.Signer, second.Algorithm)
+	assert.NoError(t, err)
+	assert.Same(t, second, same)
+	assert.Len(t, manager.KeySet().Keys, 2)
+
+	expired, _ := NewKeyManager(WithGracePeriod(0))
+	_, _ = expired.Rotate()
+	assert.Len(t, expired.KeySet().Keys, 1)
+
+	// the next key is published a max-age before it becomes current
+	rotating, _ := NewKeyManager(WithRotationInterval(time.Hour), WithKeySetMaxAge(10*time.Minute))
+	first, _ = rotating.Current()
+	rotating.activated = time.Now().Add(-55 * time.Minute)
+	current, _ := rotating.Current()
+	assert.Same(t, first, current)
+	if !assert.Len(t, rotating.KeySet().Keys, 2) {
+		return
+	}
+	next := rotating.KeySet().Keys[1].Kid
+	rotating.activated = time.Now().Add(-2 * time.Hour)
+	current, _ = rotating.Current()
+	assert.Same(t, first, current)
+	rotating.next.Created = time.Now().Add(-10 * time.Minute)
+	current, _ = rotating.Current()
+	assert.Equal(t, next, current.KeyID)
+	assert.Len(t, rotating.KeySet().Keys, 2)
+}
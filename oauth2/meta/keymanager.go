@@ -0,0 +1,281 @@
+package meta
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SigningKey is a private signing key managed by a KeyManager.
+type SigningKey struct {
+	KeyID     string
+	Algorithm string
+	Signer    crypto.Signer
+	Created   time.Time
+	// Retired is set once the key has been replaced; it stays published until the grace period ends.
+	Retired time.Time
+}
+
+// KeyGenerator creates a new signing key and its JWS algorithm.
+type KeyGenerator func() (crypto.Signer, string, error)
+
+// GenerateES256 generates a P-256 ECDSA key for ES256.
+func GenerateES256() (crypto.Signer, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return key, "ES256", err
+}
+
+// KeyManager holds the current signing key and recently retired keys. Retired keys remain in the
+// published key set for a grace period so that tokens and metadata signed with them still verify.
+// With a rotation interval, the next key is published at least the key set max-age before it
+// becomes current, so that clients with a cached key set can verify it.
+type KeyManager struct {
+	generate  KeyGenerator
+	rotation  time.Duration
+	grace     time.Duration
+	maxAge    time.Duration
+	current   *SigningKey
+	activated time.Time
+	next      *SigningKey
+	retired   []*SigningKey
+	mux       sync.RWMutex
+}
+
+// KeyManagerOption customizes a KeyManager.
+type KeyManagerOption func(m *KeyManager)
+
+// WithKeyGenerator sets the generator used for new keys; GenerateES256 is used by default.
+func WithKeyGenerator(generate KeyGenerator) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.generate = generate
+	}
+}
+
+// WithRotationInterval rotates the current key automatically once it is older than interval.
+func WithRotationInterval(interval time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.rotation = interval
+	}
+}
+
+// WithGracePeriod sets how long retired keys remain published; the default is 24 hours.
+func WithGracePeriod(grace time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.grace = grace
+	}
+}
+
+// WithKeySetMaxAge sets the Cache-Control max-age of the served key set; it should not exceed the grace period.
+func WithKeySetMaxAge(maxAge time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.maxAge = maxAge
+	}
+}
+
+// Current returns the current signing key. Once the rotation interval is within the key set
+// max-age of elapsing, the next key is generated and published; it becomes current when the
+// interval elapsed and it has been published for at least the max-age.
+func (m *KeyManager) Current() (*SigningKey, error) {
+	m.mux.RLock()
+	current, next, due := m.current, m.next, m.activated.Add(m.rotation)
+	m.mux.RUnlock()
+	if m.rotation <= 0 {
+		return current, nil
+	}
+	now := time.Now()
+	if next == nil && now.Before(due.Add(-m.maxAge)) {
+		return current, nil
+	}
+	if next != nil && (now.Before(due) || now.Sub(next.Created) < m.maxAge) {
+		return current, nil
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.current != current || m.next != next { // rotated concurrently
+		return m.current, nil
+	}
+	var err error
+	if next == nil {
+		if next, err = m.newKey(); err != nil {
+			return nil, err
+		}
+		m.next = next
+	}
+	if !now.Before(due) && time.Since(next.Created) >= m.maxAge {
+		m.replace(next)
+	}
+	return m.current, nil
+}
+
+// Rotate replaces the current key right away with the published next key, or a newly generated one.
+func (m *KeyManager) Rotate() (*SigningKey, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	key := m.next
+	if key == nil {
+		var err error
+		if key, err = m.newKey(); err != nil {
+			return nil, err
+		}
+	}
+	m.replace(key)
+	return m.current, nil
+}
+
+// RotateTo replaces the current key with signer, e.g. a key held in a KMS or HSM. Rotating to the
+// current key leaves the key set unchanged.
+func (m *KeyManager) RotateTo(signer crypto.Signer, algorithm string) (*SigningKey, error) {
+	key, err := newSigningKey(signer, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.current != nil && m.current.KeyID == key.KeyID {
+		return m.current, nil
+	}
+	m.replace(key)
+	return key, nil
+}
+
+// KeySet returns the public keys of the current key, of the next key once published and of retired
+// keys still within the grace period.
+func (m *KeyManager) KeySet() *JSONWebKeySet {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.prune()
+	ret := &JSONWebKeySet{}
+	for _, key := range m.published() {
+		jwk, err := NewJSONWebKey(key.Signer.Public())
+		if err != nil {
+			continue // validated when the key was added
+		}
+		jwk.Kid = key.KeyID
+		jwk.Alg = key.Algorithm
+		jwk.Use = "sig"
+		ret.Keys = append(ret.Keys, *jwk)
+	}
+	return ret
+}
+
+// PublicKeys returns the published verification keys by key ID.
+func (m *KeyManager) PublicKeys() map[string]crypto.PublicKey {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.prune()
+	keys := m.published()
+	ret := make(map[string]crypto.PublicKey, len(keys))
+	for _, key := range keys {
+		ret[key.KeyID] = key.Signer.Public()
+	}
+	return ret
+}
+
+// published returns the current key, the next key and retired keys, in that order.
+func (m *KeyManager) published() []*SigningKey {
+	ret := []*SigningKey{m.current}
+	if m.next != nil {
+		ret = append(ret, m.next)
+	}
+	return append(ret, m.retired...)
+}
+
+// ServeHTTP serves the published key set, e.g. at the jwks_uri.
+func (m *KeyManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := m.Current(); err != nil {
+		http.Error(w, "failed to rotate signing key", http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(m.KeySet())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(m.maxAge.Seconds())))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(body)
+	}
+}
+
+func (m *KeyManager) newKey() (*SigningKey, error) {
+	signer, algorithm, err := m.generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return newSigningKey(signer, algorithm)
+}
+
+// replace makes key current and retires the previous one; a key is published only once.
+func (m *KeyManager) replace(key *SigningKey) {
+	now := time.Now()
+	if m.next != nil && m.next.KeyID == key.KeyID {
+		m.next = nil
+	}
+	retired := m.retired[:0]
+	for _, r := range m.retired {
+		if r.KeyID != key.KeyID {
+			retired = append(retired, r)
+		}
+	}
+	m.retired = retired
+	if m.current != nil {
+		m.current.Retired = now
+		m.retired = append([]*SigningKey{m.current}, m.retired...)
+	}
+	key.Retired = time.Time{}
+	m.current, m.activated = key, now
+	m.prune()
+}
+
+// prune drops retired keys whose grace period ended.
+func (m *KeyManager) prune() {
+	now := time.Now()
+	kept := m.retired[:0]
+	for _, key := range m.retired {
+		if now.Sub(key.Retired) < m.grace {
+			kept = append(kept, key)
+		}
+	}
+	m.retired = kept
+}
+
+// newSigningKey wraps signer, using the RFC 7638 thumbprint of its public key as key ID.
+func newSigningKey(signer crypto.Signer, algorithm string) (*SigningKey, error) {
+	jwk, err := NewJSONWebKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	kid, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{KeyID: kid, Algorithm: algorithm, Signer: signer, Created: time.Now()}, nil
+}
+
+// NewKeyManager creates a KeyManager with a freshly generated current key.
+func NewKeyManager(options ...KeyManagerOption) (*KeyManager, error) {
+	ret := &KeyManager{generate: GenerateES256, grace: 24 * time.Hour, maxAge: DefaultMaxAge}
+	for _, option := range options {
+		option(ret)
+	}
+	key, err := ret.newKey()
+	if err != nil {
+		return nil, err
+	}
+	ret.replace(key)
+	return ret, nil
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 )
 
 // ProtectedResourceMetadata represents the full JSON object defined in
@@ -78,3 +81,49 @@ func FetchProtectedResourceMetadata(ctx context.Context, metadataURL string, cli
 	}
 	return &resource, nil
 }
+
+// ProtectedResourceMetadataSuffix is the well-known URI suffix registered by RFC 9728 §3.
+const ProtectedResourceMetadataSuffix = "/.well-known/oauth-protected-resource"
+
+// ProtectedResourceMetadataURL returns the metadata URL for a resource identifier, inserting the
+// well-known suffix between the host and the resource path as specified by RFC 9728 §3.1.
+func ProtectedResourceMetadataURL(resource string) (string, error) {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return "", fmt.Errorf("resource URL parse error: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("resource URL %q is not absolute", resource)
+	}
+	u.Path = ProtectedResourceMetadataSuffix + strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// UnmarshalJSON custom unmarshal to preserve unknown members in Extra.
+func (p *ProtectedResourceMetadata) UnmarshalJSON(data []byte) error {
+	type alias ProtectedResourceMetadata
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*p = ProtectedResourceMetadata(a)
+	extra, err := ExtractExtra(data, reflect.TypeOf(a))
+	if err != nil {
+		return err
+	}
+	p.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes Extra back out.
+func (p ProtectedResourceMetadata) MarshalJSON() ([]byte, error) {
+	type alias ProtectedResourceMetadata
+	core, err := json.Marshal(alias(p))
+	if err != nil {
+		return nil, err
+	}
+	return MergeExtra(core, p.Extra)
+}
@@ -0,0 +1,108 @@
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SignedMetadataMember is the metadata member carrying the signed metadata JWT (RFC 8414 §2.1, RFC 9728 §2.2).
+const SignedMetadataMember = "signed_metadata"
+
+// Signer creates a signed_metadata JWT whose claims are the supplied metadata members.
+type Signer interface {
+	SignMetadata(claims map[string]any) (string, error)
+}
+
+// Verifier verifies a signed_metadata JWT and returns its claims.
+type Verifier interface {
+	VerifyMetadata(signed string) (map[string]any, error)
+}
+
+var (
+	// ErrNoSignedMetadata is returned when verification is requested for a document without signed_metadata.
+	ErrNoSignedMetadata = errors.New("metadata has no signed_metadata")
+	// ErrSignedMetadataIssuer is returned when signed_metadata was attested for another issuer or resource.
+	ErrSignedMetadataIssuer = errors.New("signed_metadata issuer mismatch")
+)
+
+// registeredClaims are JWT claims that are not metadata members.
+var registeredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// VerifySignedMetadata verifies signed_metadata and applies its claims, which take precedence over plain members.
+// The iss claim must be the resource, so that metadata signed for one resource cannot be replayed for another.
+func (p *ProtectedResourceMetadata) VerifySignedMetadata(verifier Verifier) error {
+	return verifySignedMetadata(p, p.Extra, "resource", p.Resource, verifier)
+}
+
+// VerifySignedMetadata verifies signed_metadata and applies its claims, which take precedence over plain members.
+// The iss claim must be the issuer, so that metadata signed for one server cannot be replayed for another.
+func (m *AuthorizationServerMetadata) VerifySignedMetadata(verifier Verifier) error {
+	return verifySignedMetadata(m, m.Extra, "issuer", m.Issuer, verifier)
+}
+
+// verifySignedMetadata applies verified claims to target, whose identity is the member named identityMember with value identity.
+func verifySignedMetadata(target any, extra map[string]any, identityMember, identity string, verifier Verifier) error {
+	signed, _ := extra[SignedMetadataMember].(string)
+	if signed == "" {
+		return ErrNoSignedMetadata
+	}
+	claims, err := verifier.VerifyMetadata(signed)
+	if err != nil {
+		return fmt.Errorf("invalid signed_metadata: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss == "" || iss != identity {
+		return fmt.Errorf("%w: expected %q, got %q", ErrSignedMetadataIssuer, identity, iss)
+	}
+	if signedIdentity, ok := claims[identityMember]; ok && signedIdentity != identity {
+		return fmt.Errorf("%w: signed %s %v does not match %q", ErrSignedMetadataIssuer, identityMember, signedIdentity, identity)
+	}
+	plain, err := metadataClaims(target)
+	if err != nil {
+		return err
+	}
+	for _, claim := range registeredClaims {
+		delete(claims, claim)
+	}
+	for k, v := range claims {
+		plain[k] = v
+	}
+	plain[SignedMetadataMember] = signed
+	data, err := json.Marshal(plain)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// signMetadata returns the metadata members of document with a signed_metadata member added.
+func signMetadata(document any, signer Signer) (map[string]any, error) {
+	claims, err := metadataClaims(document)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]any, len(claims)+1)
+	for k, v := range claims {
+		ret[k] = v
+	}
+	signed, err := signer.SignMetadata(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign metadata: %w", err)
+	}
+	ret[SignedMetadataMember] = signed
+	return ret, nil
+}
+
+// metadataClaims returns the JSON members of a metadata document, excluding signed_metadata.
+func metadataClaims(document any) (map[string]any, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string]any
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	delete(ret, SignedMetadataMember)
+	return ret, nil
+}
@@ -0,0 +1,90 @@
+package meta
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// NewJSONWebKeyFromCertificates creates a JSON Web Key for the leaf certificate's public key
+// carrying the x5c chain and x5t#S256 thumbprint; chain starts with the leaf certificate.
+func NewJSONWebKeyFromCertificates(chain ...*x509.Certificate) (*JSONWebKey, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("certificate chain was empty")
+	}
+	ret, err := NewJSONWebKey(chain[0].PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range chain {
+		ret.X5c = append(ret.X5c, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	ret.X5tS256 = CertificateThumbprint(chain[0])
+	return ret, nil
+}
+
+// CertificateThumbprint returns the base64url SHA-256 thumbprint of the DER certificate (x5t#S256).
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Certificates parses the x5c chain; x5c members use standard base64, not base64url (RFC 7517 §4.7).
+func (k *JSONWebKey) Certificates() ([]*x509.Certificate, error) {
+	ret := make([]*x509.Certificate, 0, len(k.X5c))
+	for i, encoded := range k.X5c {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("x5c[%d]: %w", i, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("x5c[%d]: %w", i, err)
+		}
+		ret = append(ret, cert)
+	}
+	return ret, nil
+}
+
+// VerifyCertificates validates the x5c chain with options and checks that the leaf certificate
+// matches the key and the x5t#S256 thumbprint. Certificates after the leaf are used as intermediates
+// unless options already supplies them. It returns the verified chains.
+func (k *JSONWebKey) VerifyCertificates(options x509.VerifyOptions) ([][]*x509.Certificate, error) {
+	chain, err := k.Certificates()
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("key has no x5c certificate chain")
+	}
+	leaf := chain[0]
+	if k.Kty != "" {
+		expected, err := k.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		certKey, err := NewJSONWebKey(leaf.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if actual, _ := certKey.Thumbprint(); actual != expected {
+			return nil, errors.New("x5c leaf certificate does not match the key")
+		}
+	}
+	if k.X5tS256 != "" && k.X5tS256 != CertificateThumbprint(leaf) {
+		return nil, errors.New("x5t#S256 does not match the x5c leaf certificate")
+	}
+	if options.Intermediates == nil {
+		options.Intermediates = x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			options.Intermediates.AddCert(cert)
+		}
+	}
+	chains, err := leaf.Verify(options)
+	if err != nil {
+		return nil, fmt.Errorf("x5c chain verification failed: %w", err)
+	}
+	return chains, nil
+}
@@ -0,0 +1,45 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// NewHTTPClient returns an HTTP client presenting certificate on TLS connections. rootCAs
+// optionally replaces the system roots used to verify servers.
+func NewHTTPClient(certificate tls.Certificate, rootCAs *x509.CertPool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport}
+}
+
+// LoadHTTPClient loads a PEM certificate and key and returns an HTTP client presenting it.
+func LoadHTTPClient(certFile, keyFile string) (*http.Client, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return NewHTTPClient(certificate, nil), nil
+}
+
+// ServerTLSConfig returns a server TLS configuration that requests client certificates without
+// requiring them, so that both bearer and certificate-bound tokens can be served. When clientCAs
+// is nil any certificate is accepted, since binding relies on the certificate thumbprint (RFC 8705 §2.2).
+func ServerTLSConfig(certificate tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	ret := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequestClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAs != nil {
+		ret.ClientCAs = clientCAs
+		ret.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return ret
+}
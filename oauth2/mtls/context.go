@@ -0,0 +1,20 @@
+package mtls
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+type peerCertificateKey struct{}
+
+// WithPeerCertificate returns a context carrying the client certificate, for servers where TLS is
+// terminated outside the http.Request (e.g. a custom listener or a trusted proxy).
+func WithPeerCertificate(ctx context.Context, certificate *x509.Certificate) context.Context {
+	return context.WithValue(ctx, peerCertificateKey{}, certificate)
+}
+
+// PeerCertificateFromContext returns the client certificate stored by WithPeerCertificate.
+func PeerCertificateFromContext(ctx context.Context) (*x509.Certificate, bool) {
+	ret, ok := ctx.Value(peerCertificateKey{}).(*x509.Certificate)
+	return ret, ok && ret != nil
+}
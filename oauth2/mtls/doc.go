@@ -0,0 +1,9 @@
+// Package mtls implements mutual-TLS certificate-bound access tokens (RFC 8705).
+//
+// Servers use a Verifier to check that the cnf.x5t#S256 confirmation of an
+// access token matches the certificate the client presented on the TLS
+// connection, and to reject unbound tokens when the protected resource
+// advertises tls_client_certificate_bound_access_tokens. Clients use
+// NewHTTPClient or LoadHTTPClient to present a client certificate on token
+// requests and resource calls.
+package mtls
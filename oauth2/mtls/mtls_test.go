@@ -0,0 +1,101 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+func newCertificate(t *testing.T, name string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	assert.NoError(t, err)
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestVerifier_VerifyRequest(t *testing.T) {
+	clientCertificate := newCertificate(t, "client")
+	otherCertificate := newCertificate(t, "other")
+	bound := map[string]any{"cnf": map[string]any{"x5t#S256": meta.CertificateThumbprint(clientCertificate.Leaf)}}
+
+	var claims map[string]any
+	var verifier *Verifier
+	var verifyErr error
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifyErr = verifier.VerifyRequest(r, claims)
+	}))
+	srv.TLS = ServerTLSConfig(newCertificate(t, "localhost"), nil)
+	srv.StartTLS()
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	testCases := []struct {
+		name        string
+		certificate *tls.Certificate
+		claims      map[string]any
+		required    bool
+		expectErr   error
+	}{
+		{name: "bound", certificate: &clientCertificate, claims: bound},
+		{name: "wrong certificate", certificate: &otherCertificate, claims: bound, expectErr: ErrCertificateMismatch},
+		{name: "no certificate", claims: bound, expectErr: ErrNoClientCertificate},
+		{name: "unbound optional", claims: map[string]any{}},
+		{name: "unbound required", certificate: &clientCertificate, claims: map[string]any{}, required: true, expectErr: ErrUnboundToken},
+	}
+	for _, tc := range testCases {
+		claims = tc.claims
+		verifier = NewVerifier(&meta.ProtectedResourceMetadata{TLSClientCertificateBoundAccessTokens: tc.required})
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		if tc.certificate != nil {
+			client = NewHTTPClient(*tc.certificate, roots)
+		}
+		resp, err := client.Get(srv.URL)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		resp.Body.Close()
+		if tc.expectErr != nil {
+			assert.True(t, errors.Is(verifyErr, tc.expectErr), tc.name)
+			continue
+		}
+		assert.NoError(t, verifyErr, tc.name)
+	}
+}
+
+func TestVerifier_CertificateHeader(t *testing.T) {
+	certificate := newCertificate(t, "client")
+	claims := map[string]any{"cnf": map[string]any{"x5t#S256": meta.CertificateThumbprint(certificate.Leaf)}}
+	encoded := url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Leaf.Raw})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Client-Cert", encoded)
+	assert.ErrorIs(t, NewVerifier(nil).VerifyRequest(req, claims), ErrNoClientCertificate)
+	assert.NoError(t, NewVerifier(nil, WithCertificateHeader("X-Client-Cert")).VerifyRequest(req, claims))
+}
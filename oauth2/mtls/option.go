@@ -0,0 +1,20 @@
+package mtls
+
+// Option customizes a Verifier.
+type Option func(v *Verifier)
+
+// WithRequired requires every token to be certificate-bound regardless of the resource metadata.
+func WithRequired(required bool) Option {
+	return func(v *Verifier) {
+		v.required = required
+	}
+}
+
+// WithCertificateHeader reads the client certificate from a header set by a TLS-terminating proxy
+// (URL-escaped PEM, e.g. nginx $ssl_client_escaped_cert). Only enable it behind a proxy that strips
+// the header from client requests.
+func WithCertificateHeader(name string) Option {
+	return func(v *Verifier) {
+		v.certificateHeader = name
+	}
+}
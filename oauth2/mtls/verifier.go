@@ -0,0 +1,124 @@
+package mtls
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/viant/mcp-protocol/oauth2/challenge"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+var (
+	// ErrNoClientCertificate is returned when a certificate-bound token is presented without a client certificate.
+	ErrNoClientCertificate = errors.New("client certificate required for certificate-bound access token")
+	// ErrCertificateMismatch is returned when the client certificate does not match cnf.x5t#S256.
+	ErrCertificateMismatch = errors.New("client certificate does not match access token binding")
+	// ErrUnboundToken is returned when the resource requires certificate-bound tokens and the token is not bound.
+	ErrUnboundToken = errors.New("access token is not bound to a client certificate")
+)
+
+// Verifier checks certificate-bound access tokens against the client certificate of a request (RFC 8705 §3).
+type Verifier struct {
+	required          bool
+	certificateHeader string
+}
+
+// VerifyRequest verifies the access token claims (or introspection response) against the peer
+// certificate of r. Unbound tokens pass unless binding is required.
+func (v *Verifier) VerifyRequest(r *http.Request, claims map[string]any) error {
+	certificate, err := v.PeerCertificate(r)
+	if err != nil {
+		return err
+	}
+	return v.Verify(certificate, claims)
+}
+
+// Verify verifies the access token claims against certificate, which is nil when none was presented.
+func (v *Verifier) Verify(certificate *x509.Certificate, claims map[string]any) error {
+	expected := ConfirmationThumbprint(claims)
+	if expected == "" {
+		if v.required {
+			return ErrUnboundToken
+		}
+		return nil
+	}
+	if certificate == nil {
+		return ErrNoClientCertificate
+	}
+	actual := meta.CertificateThumbprint(certificate)
+	if subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) != 1 {
+		return ErrCertificateMismatch
+	}
+	return nil
+}
+
+// PeerCertificate returns the client certificate of r from its TLS connection state, the request
+// context (WithPeerCertificate) or, when configured, the trusted proxy header. It returns nil when
+// the client presented no certificate.
+func (v *Verifier) PeerCertificate(r *http.Request) (*x509.Certificate, error) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0], nil
+	}
+	if certificate, ok := PeerCertificateFromContext(r.Context()); ok {
+		return certificate, nil
+	}
+	if v.certificateHeader == "" {
+		return nil, nil
+	}
+	value := r.Header.Get(v.certificateHeader)
+	if value == "" {
+		return nil, nil
+	}
+	return parseCertificateHeader(value)
+}
+
+// Challenge returns the Bearer challenge for a verification error (RFC 8705 §3, RFC 6750 §3.1).
+func (v *Verifier) Challenge(err error) *challenge.Challenge {
+	ret := &challenge.Challenge{Scheme: challenge.SchemeBearer, Params: map[string]string{}}
+	if err != nil {
+		ret.Params["error"] = challenge.ErrorInvalidToken
+		ret.Params["error_description"] = err.Error()
+	}
+	return ret
+}
+
+// ConfirmationThumbprint returns the cnf.x5t#S256 member of access token claims or introspection response (RFC 8705 §3.1).
+func ConfirmationThumbprint(claims map[string]any) string {
+	cnf, _ := claims["cnf"].(map[string]any)
+	thumbprint, _ := cnf["x5t#S256"].(string)
+	return thumbprint
+}
+
+// parseCertificateHeader decodes a URL-escaped PEM certificate as forwarded by common proxies.
+func parseCertificateHeader(value string) (*x509.Certificate, error) {
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		value = unescaped
+	}
+	block, _ := pem.Decode([]byte(value))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("client certificate header is not a PEM certificate")
+	}
+	ret, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate header: %w", err)
+	}
+	return ret, nil
+}
+
+// NewVerifier creates a Verifier for the protected resource; certificate-bound tokens are required
+// when metadata advertises tls_client_certificate_bound_access_tokens.
+func NewVerifier(metadata *meta.ProtectedResourceMetadata, options ...Option) *Verifier {
+	ret := &Verifier{}
+	if metadata != nil {
+		ret.required = metadata.TLSClientCertificateBoundAccessTokens
+	}
+	for _, option := range options {
+		option(ret)
+	}
+	return ret
+}
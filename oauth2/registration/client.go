@@ -0,0 +1,215 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// ErrNotRegistered is returned by management operations when no client is stored for the issuer.
+var ErrNotRegistered = errors.New("client is not registered")
+
+// Client registers and manages OAuth 2.0 clients at authorization servers.
+type Client struct {
+	httpClient         *http.Client
+	initialAccessToken string
+	store              Store
+}
+
+// Store returns the store holding issued client information.
+func (c *Client) Store() Store {
+	return c.store
+}
+
+// Ensure returns stored client information for the authorization server, registering a new client
+// when none is stored or the stored client secret has expired.
+func (c *Client) Ensure(ctx context.Context, server *meta.AuthorizationServerMetadata, metadata *ClientMetadata) (*ClientInformation, error) {
+	info, err := c.store.Get(ctx, server.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil && !info.Expired() {
+		return info, nil
+	}
+	return c.Register(ctx, server, metadata)
+}
+
+// Register registers a new client at the authorization server registration endpoint (RFC 7591 §3.1)
+// and stores the issued client information under the server issuer.
+func (c *Client) Register(ctx context.Context, server *meta.AuthorizationServerMetadata, metadata *ClientMetadata) (*ClientInformation, error) {
+	if server == nil || server.RegistrationEndpoint == "" {
+		return nil, errors.New("authorization server does not support dynamic client registration")
+	}
+	if metadata == nil {
+		return nil, errors.New("client metadata was nil")
+	}
+	info, err := c.do(ctx, http.MethodPost, server.RegistrationEndpoint, c.initialAccessToken, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.Put(ctx, server.Issuer, info); err != nil {
+		return nil, fmt.Errorf("failed to store client information: %w", err)
+	}
+	return info, nil
+}
+
+// Read retrieves the current client configuration from the client configuration endpoint (RFC 7592 §2.1).
+func (c *Client) Read(ctx context.Context, issuer string) (*ClientInformation, error) {
+	current, err := c.managed(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.do(ctx, http.MethodGet, current.RegistrationClientURI, current.RegistrationAccessToken, nil)
+	if err != nil {
+		return nil, err
+	}
+	return info, c.put(ctx, issuer, current, info)
+}
+
+// Update replaces the client metadata at the client configuration endpoint (RFC 7592 §2.2).
+func (c *Client) Update(ctx context.Context, issuer string, metadata *ClientMetadata) (*ClientInformation, error) {
+	current, err := c.managed(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, errors.New("client metadata was nil")
+	}
+	request := &ClientInformation{ClientMetadata: *metadata}
+	request.ClientID = current.ClientID
+	request.ClientSecret = current.ClientSecret
+	info, err := c.do(ctx, http.MethodPut, current.RegistrationClientURI, current.RegistrationAccessToken, request)
+	if err != nil {
+		return nil, err
+	}
+	return info, c.put(ctx, issuer, current, info)
+}
+
+// Delete deprovisions the client at the client configuration endpoint (RFC 7592 §2.3) and removes it from the store.
+func (c *Client) Delete(ctx context.Context, issuer string) error {
+	current, err := c.managed(ctx, issuer)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, current.RegistrationClientURI, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+current.RegistrationAccessToken)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK, http.StatusUnauthorized, http.StatusNotFound:
+		// RFC 7592 §2.3: 401 indicates the client is no longer valid (already deleted)
+	default:
+		return decodeError(resp)
+	}
+	return c.store.Delete(ctx, issuer)
+}
+
+// managed returns stored client information that supports the RFC 7592 management protocol.
+func (c *Client) managed(ctx context.Context, issuer string) (*ClientInformation, error) {
+	info, err := c.store.Get(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotRegistered, issuer)
+	}
+	if info.RegistrationClientURI == "" || info.RegistrationAccessToken == "" {
+		return nil, fmt.Errorf("client %s at %s does not support configuration management", info.ClientID, issuer)
+	}
+	return info, nil
+}
+
+// put stores updated client information, carrying over management credentials the server did not reissue.
+func (c *Client) put(ctx context.Context, issuer string, current, info *ClientInformation) error {
+	if info.RegistrationAccessToken == "" {
+		info.RegistrationAccessToken = current.RegistrationAccessToken
+	}
+	if info.RegistrationClientURI == "" {
+		info.RegistrationClientURI = current.RegistrationClientURI
+	}
+	if info.ClientSecret == "" {
+		info.ClientSecret = current.ClientSecret
+	}
+	return c.store.Put(ctx, issuer, info)
+}
+
+func (c *Client) do(ctx context.Context, method, URL, bearer string, payload any) (*ClientInformation, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode client metadata: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+	var info ClientInformation
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode client information: %w", err)
+	}
+	if info.ClientID == "" {
+		return nil, errors.New("client information has no client_id")
+	}
+	return &info, nil
+}
+
+func decodeError(resp *http.Response) error {
+	ret := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err := json.Unmarshal(data, ret); err != nil || ret.Code == "" {
+		ret.Code = http.StatusText(resp.StatusCode)
+		ret.Description = string(data)
+	}
+	return ret
+}
+
+// Expired reports whether the issued client secret has expired.
+func (c *ClientInformation) Expired() bool {
+	return c.ClientSecretExpiresAt > 0 && time.Now().Unix() >= c.ClientSecretExpiresAt
+}
+
+// New creates a registration client.
+func New(options ...Option) *Client {
+	ret := &Client{}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = http.DefaultClient
+	}
+	if ret.store == nil {
+		ret.store = NewMemoryStore()
+	}
+	return ret
+}
@@ -0,0 +1,213 @@
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// registrationServer is an RFC 7591/7592 registration endpoint holding a single client.
+type registrationServer struct {
+	*httptest.Server
+	registrations int
+	client        map[string]any
+	deleted       bool
+}
+
+func newRegistrationServer(t *testing.T, secretExpiresAt int64) *registrationServer {
+	ret := &registrationServer{}
+	ret.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/register" && r.Method == http.MethodPost:
+			if r.Header.Get("Authorization") != "Bearer initial" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_token"}`))
+				return
+			}
+			ret.registrations++
+			ret.client = nil
+			_ = json.NewDecoder(r.Body).Decode(&ret.client)
+			if _, ok := ret.client["redirect_uris"]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_redirect_uri","error_description":"redirect_uris is required"}`))
+				return
+			}
+			ret.client["client_id"] = "client-1"
+			ret.client["client_secret"] = "secret-1"
+			ret.client["client_secret_expires_at"] = secretExpiresAt
+			ret.client["registration_access_token"] = "rat"
+			ret.client["registration_client_uri"] = ret.URL + "/register/client-1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(ret.client)
+		case r.URL.Path == "/register/client-1":
+			assert.Equal(t, "Bearer rat", r.Header.Get("Authorization"))
+			switch r.Method {
+			case http.MethodGet:
+				// management credentials are not reissued on read
+				_ = json.NewEncoder(w).Encode(map[string]any{"client_id": "client-1", "client_name": ret.client["client_name"]})
+			case http.MethodPut:
+				var update map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&update)
+				assert.Equal(t, "client-1", update["client_id"])
+				assert.Equal(t, "secret-1", update["client_secret"])
+				ret.client["client_name"] = update["client_name"]
+				_ = json.NewEncoder(w).Encode(map[string]any{"client_id": "client-1", "client_name": update["client_name"]})
+			case http.MethodDelete:
+				ret.deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ret.Close)
+	return ret
+}
+
+func (s *registrationServer) metadata() *meta.AuthorizationServerMetadata {
+	return &meta.AuthorizationServerMetadata{Issuer: s.URL, RegistrationEndpoint: s.URL + "/register"}
+}
+
+func TestClient_Ensure(t *testing.T) {
+	testCases := []struct {
+		description     string
+		secretExpiresAt int64
+		registrations   int
+	}{
+		{description: "non-expiring client is reused", secretExpiresAt: 0, registrations: 1},
+		{description: "expired client is registered again", secretExpiresAt: time.Now().Add(-time.Minute).Unix(), registrations: 2},
+	}
+	for _, testCase := range testCases {
+		srv := newRegistrationServer(t, testCase.secretExpiresAt)
+		client := New(WithHTTPClient(srv.Client()), WithInitialAccessToken("initial"))
+		metadata := NewClientMetadata("test", "http://127.0.0.1/callback")
+
+		info, err := client.Ensure(context.Background(), srv.metadata(), metadata)
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, "client-1", info.ClientID, testCase.description)
+		_, err = client.Ensure(context.Background(), srv.metadata(), metadata)
+		assert.NoError(t, err, testCase.description)
+		assert.Equal(t, testCase.registrations, srv.registrations, testCase.description)
+	}
+}
+
+func TestClient_Register(t *testing.T) {
+	srv := newRegistrationServer(t, 0)
+	testCases := []struct {
+		description string
+		server      *meta.AuthorizationServerMetadata
+		options     []Option
+		metadata    *ClientMetadata
+		code        string
+		expectErr   bool
+	}{
+		{description: "registered", server: srv.metadata(), options: []Option{WithInitialAccessToken("initial")}, metadata: NewClientMetadata("test", "http://127.0.0.1/callback")},
+		{description: "missing initial access token", server: srv.metadata(), metadata: NewClientMetadata("test", "http://127.0.0.1/callback"), code: "invalid_token", expectErr: true},
+		{description: "rejected metadata", server: srv.metadata(), options: []Option{WithInitialAccessToken("initial")}, metadata: &ClientMetadata{ClientName: "test"}, code: "invalid_redirect_uri", expectErr: true},
+		{description: "no registration endpoint", server: &meta.AuthorizationServerMetadata{Issuer: srv.URL}, metadata: NewClientMetadata("test"), expectErr: true},
+	}
+	for _, testCase := range testCases {
+		client := New(append(testCase.options, WithHTTPClient(srv.Client()))...)
+		info, err := client.Register(context.Background(), testCase.server, testCase.metadata)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.description)
+			var registrationErr *Error
+			if testCase.code != "" && assert.True(t, errors.As(err, &registrationErr), testCase.description) {
+				assert.Equal(t, testCase.code, registrationErr.Code, testCase.description)
+			}
+			continue
+		}
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, "secret-1", info.ClientSecret, testCase.description)
+		assert.Equal(t, "test", info.ClientName, testCase.description)
+		stored, err := client.Store().Get(context.Background(), srv.URL)
+		assert.NoError(t, err, testCase.description)
+		assert.Equal(t, info, stored, testCase.description)
+	}
+}
+
+func TestClient_Management(t *testing.T) {
+	srv := newRegistrationServer(t, 0)
+	client := New(WithHTTPClient(srv.Client()), WithInitialAccessToken("initial"))
+	ctx := context.Background()
+
+	_, err := client.Read(ctx, srv.URL)
+	assert.ErrorIs(t, err, ErrNotRegistered)
+
+	_, err = client.Register(ctx, srv.metadata(), NewClientMetadata("test", "http://127.0.0.1/callback"))
+	assert.NoError(t, err)
+
+	info, err := client.Read(ctx, srv.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", info.ClientName)
+	assert.Equal(t, "rat", info.RegistrationAccessToken)
+	assert.Equal(t, "secret-1", info.ClientSecret)
+
+	updated := NewClientMetadata("renamed", "http://127.0.0.1/callback")
+	info, err = client.Update(ctx, srv.URL, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", info.ClientName)
+	stored, _ := client.Store().Get(ctx, srv.URL)
+	assert.Equal(t, "renamed", stored.ClientName)
+	assert.Equal(t, srv.URL+"/register/client-1", stored.RegistrationClientURI)
+
+	assert.NoError(t, client.Delete(ctx, srv.URL))
+	assert.True(t, srv.deleted)
+	stored, _ = client.Store().Get(ctx, srv.URL)
+	assert.Nil(t, stored)
+}
+
+func TestClientMetadata_Extra(t *testing.T) {
+	input := `{"client_id":"client-1","client_name":"test","redirect_uris":["http://127.0.0.1/callback"],"id_token_signed_response_alg":"RS256"}`
+	var info ClientInformation
+	assert.NoError(t, json.Unmarshal([]byte(input), &info))
+	assert.Equal(t, "client-1", info.ClientID)
+	assert.Equal(t, map[string]any{"id_token_signed_response_alg": "RS256"}, info.Extra)
+
+	data, err := json.Marshal(info)
+	assert.NoError(t, err)
+	assert.JSONEq(t, input, string(data))
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clients", "registration.json")
+	ctx := context.Background()
+	info := &ClientInformation{
+		ClientCredentials: ClientCredentials{ClientID: "client-1", ClientSecret: "secret-1"},
+		ClientMetadata:    ClientMetadata{ClientName: "test", Extra: map[string]any{"custom": "value"}},
+	}
+
+	store := NewFileStore(path)
+	missing, err := store.Get(ctx, "https://as.example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+	assert.NoError(t, store.Put(ctx, "https://as.example.com", info))
+
+	stat, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+	}
+	stored, err := NewFileStore(path).Get(ctx, "https://as.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, info, stored)
+
+	assert.NoError(t, store.Delete(ctx, "https://as.example.com"))
+	stored, err = store.Get(ctx, "https://as.example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.NoError(t, store.Delete(ctx, "https://as.example.com"))
+}
@@ -0,0 +1,11 @@
+// Package registration implements an OAuth 2.0 Dynamic Client Registration
+// client (RFC 7591) together with the client configuration management
+// endpoint (RFC 7592).
+//
+// MCP clients frequently meet authorization servers they have never seen
+// before; the package builds client metadata, registers it at the
+// registration_endpoint advertised by meta.AuthorizationServerMetadata and
+// persists the issued client credentials per issuer in a Store, so the same
+// client identity is reused across sessions. NewFileStore keeps client
+// secrets in plaintext; see FileStore before using it for confidential clients.
+package registration
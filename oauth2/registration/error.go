@@ -0,0 +1,18 @@
+package registration
+
+import "fmt"
+
+// Error represents the client registration error response defined in RFC 7591 §3.2.2.
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("client registration failed (%d): %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("client registration failed (%d): %s: %s", e.StatusCode, e.Code, e.Description)
+}
@@ -0,0 +1,170 @@
+package registration
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// Grant types and token endpoint authentication methods registered by RFC 7591 §2.
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+	AuthMethodTLSClientAuth     = "tls_client_auth"
+)
+
+// ClientMetadata models the client metadata defined in RFC 7591 §2.
+type ClientMetadata struct {
+	RedirectURIs            []string `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+	ClientName              string   `json:"client_name,omitempty"`
+	ClientURI               string   `json:"client_uri,omitempty"`
+	LogoURI                 string   `json:"logo_uri,omitempty"`
+	Scope                   string   `json:"scope,omitempty"`
+	Contacts                []string `json:"contacts,omitempty"`
+	TosURI                  string   `json:"tos_uri,omitempty"`
+	PolicyURI               string   `json:"policy_uri,omitempty"`
+	JSONWebKeySetURI        string   `json:"jwks_uri,omitempty"`
+
+	JSONWebKeySet   *meta.JSONWebKeySet `json:"jwks,omitempty"`
+	SoftwareID      string              `json:"software_id,omitempty"`
+	SoftwareVersion string              `json:"software_version,omitempty"`
+	// SoftwareStatement is a signed JWT asserting metadata values about the client software (RFC 7591 §2.3).
+	SoftwareStatement string `json:"software_statement,omitempty"`
+
+	// Catch-all for extension metadata (e.g. OpenID Connect Dynamic Client Registration members)
+	Extra map[string]any `json:"-"`
+}
+
+// NewClientMetadata creates metadata for a public client using the authorization code grant.
+func NewClientMetadata(clientName string, redirectURIs ...string) *ClientMetadata {
+	return &ClientMetadata{
+		ClientName:              clientName,
+		RedirectURIs:            redirectURIs,
+		GrantTypes:              []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		ResponseTypes:           []string{"code"},
+		TokenEndpointAuthMethod: AuthMethodNone,
+	}
+}
+
+// Negotiate adjusts metadata to what the authorization server advertises: unsupported grant types
+// are dropped and the token endpoint authentication method falls back to the first supported one
+// from preferred (or the server list when preferred is empty).
+func (m *ClientMetadata) Negotiate(server *meta.AuthorizationServerMetadata, preferred ...string) {
+	if server == nil {
+		return
+	}
+	if supported := server.GrantTypesSupported; len(supported) > 0 {
+		var grantTypes []string
+		for _, grantType := range m.GrantTypes {
+			if slices.Contains(supported, grantType) {
+				grantTypes = append(grantTypes, grantType)
+			}
+		}
+		m.GrantTypes = grantTypes
+	}
+	supported := server.TokenEndpointAuthMethodsSupported
+	if len(supported) == 0 {
+		// RFC 8414 §2: default is client_secret_basic
+		supported = []string{AuthMethodClientSecretBasic}
+	}
+	if m.TokenEndpointAuthMethod != "" && slices.Contains(supported, m.TokenEndpointAuthMethod) {
+		return
+	}
+	if len(preferred) == 0 {
+		preferred = supported
+	}
+	for _, method := range preferred {
+		if slices.Contains(supported, method) {
+			m.TokenEndpointAuthMethod = method
+			return
+		}
+	}
+}
+
+// UnmarshalJSON custom unmarshal to preserve unknown members in Extra.
+func (m *ClientMetadata) UnmarshalJSON(data []byte) error {
+	type alias ClientMetadata
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*m = ClientMetadata(a)
+	// issued credentials are decoded separately by ClientInformation
+	extra, err := meta.ExtractExtra(data, reflect.TypeOf(a), reflect.TypeOf(ClientCredentials{}))
+	if err != nil {
+		return err
+	}
+	m.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes Extra back out.
+func (m ClientMetadata) MarshalJSON() ([]byte, error) {
+	type alias ClientMetadata
+	core, err := json.Marshal(alias(m))
+	if err != nil {
+		return nil, err
+	}
+	return meta.MergeExtra(core, m.Extra)
+}
+
+// ClientInformation models the client information response defined in RFC 7591 §3.2.1 and RFC 7592 §3.
+type ClientInformation struct {
+	ClientCredentials
+	ClientMetadata
+}
+
+// ClientCredentials holds the credentials issued by the authorization server.
+type ClientCredentials struct {
+	ClientID              string `json:"client_id"`
+	ClientSecret          string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt      int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt int64  `json:"client_secret_expires_at,omitempty"`
+
+	// RFC 7592 management endpoint credentials
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+}
+
+// UnmarshalJSON decodes both the client metadata and the issued credentials.
+func (c *ClientInformation) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.ClientMetadata); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.ClientCredentials)
+}
+
+// MarshalJSON encodes both the client metadata and the issued credentials.
+func (c ClientInformation) MarshalJSON() ([]byte, error) {
+	core, err := json.Marshal(c.ClientMetadata)
+	if err != nil {
+		return nil, err
+	}
+	info, err := json.Marshal(c.ClientCredentials)
+	if err != nil {
+		return nil, err
+	}
+	var base, credentials map[string]any
+	if err := json.Unmarshal(core, &base); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(info, &credentials); err != nil {
+		return nil, err
+	}
+	for k, v := range credentials {
+		base[k] = v
+	}
+	return json.Marshal(base)
+}
@@ -0,0 +1,27 @@
+package registration
+
+import "net/http"
+
+// Option customizes a Client.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client used for registration requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithInitialAccessToken sets the initial access token required by protected registration endpoints (RFC 7591 §3).
+func WithInitialAccessToken(token string) Option {
+	return func(c *Client) {
+		c.initialAccessToken = token
+	}
+}
+
+// WithStore sets the store used to persist issued client information.
+func WithStore(store Store) Option {
+	return func(c *Client) {
+		c.store = store
+	}
+}
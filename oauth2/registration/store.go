@@ -0,0 +1,137 @@
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Store persists registered client information per authorization server issuer.
+type Store interface {
+	// Get returns client information for the issuer or nil when no client has been registered.
+	Get(ctx context.Context, issuer string) (*ClientInformation, error)
+	// Put stores client information for the issuer.
+	Put(ctx context.Context, issuer string, info *ClientInformation) error
+	// Delete removes client information for the issuer.
+	Delete(ctx context.Context, issuer string) error
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	clients *syncmap.Map[string, *ClientInformation]
+}
+
+// Get returns client information for the issuer.
+func (s *MemoryStore) Get(_ context.Context, issuer string) (*ClientInformation, error) {
+	info, _ := s.clients.Get(issuer)
+	return info, nil
+}
+
+// Put stores client information for the issuer.
+func (s *MemoryStore) Put(_ context.Context, issuer string, info *ClientInformation) error {
+	s.clients.Put(issuer, info)
+	return nil
+}
+
+// Delete removes client information for the issuer.
+func (s *MemoryStore) Delete(_ context.Context, issuer string) error {
+	s.clients.Delete(issuer)
+	return nil
+}
+
+// NewMemoryStore creates an in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{clients: syncmap.NewMap[string, *ClientInformation]()}
+}
+
+// FileStore is a Store persisting client information as a JSON document keyed by issuer.
+//
+// The document is NOT encrypted: client secrets and registration access tokens are stored in
+// plaintext, protected only by the 0600 file permissions. Use it for public clients (token endpoint
+// auth method "none") or on single-user machines; otherwise implement Store on top of a secret manager.
+type FileStore struct {
+	path string
+	mux  sync.Mutex
+}
+
+// Get returns client information for the issuer.
+func (s *FileStore) Get(_ context.Context, issuer string) (*ClientInformation, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	clients, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return clients[issuer], nil
+}
+
+// Put stores client information for the issuer.
+func (s *FileStore) Put(_ context.Context, issuer string, info *ClientInformation) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	clients, err := s.load()
+	if err != nil {
+		return err
+	}
+	clients[issuer] = info
+	return s.save(clients)
+}
+
+// Delete removes client information for the issuer.
+func (s *FileStore) Delete(_ context.Context, issuer string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	clients, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := clients[issuer]; !ok {
+		return nil
+	}
+	delete(clients, issuer)
+	return s.save(clients)
+}
+
+func (s *FileStore) load() (map[string]*ClientInformation, error) {
+	clients := map[string]*ClientInformation{}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return clients, nil
+		}
+		return nil, fmt.Errorf("failed to read client store %s: %w", s.path, err)
+	}
+	if len(data) == 0 {
+		return clients, nil
+	}
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, fmt.Errorf("failed to decode client store %s: %w", s.path, err)
+	}
+	return clients, nil
+}
+
+func (s *FileStore) save(clients map[string]*ClientInformation) error {
+	data, err := json.MarshalIndent(clients, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create client store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write client store %s: %w", s.path, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// NewFileStore creates a file-backed Store at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
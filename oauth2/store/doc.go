@@ -0,0 +1,14 @@
+// Package store persists OAuth 2.0 tokens obtained by
+// authorization.ProtectedResourceTokenSource implementations so they survive
+// process restarts.
+//
+// Tokens are keyed by issuer, resource and scope. MemoryStore keeps them in
+// process; FileStore encrypts them with a user-supplied AES-GCM key and
+// serializes concurrent processes with a file lock. Update runs refreshes
+// under that lock, so rotated refresh tokens are never used twice, and
+// Revoke revokes tokens at the authorization server's revocation_endpoint
+// (RFC 7009) before removing them.
+//
+// The authcode, device and clientcredentials token sources accept a
+// TokenStore through their WithStore options.
+package store
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// fileFormat is bound to the ciphertext as additional data so files cannot be swapped between formats.
+const fileFormat = "mcp-protocol/oauth2/store.v1"
+
+// FileStore is a TokenStore persisting tokens in a single AES-GCM encrypted file. Access from
+// concurrent processes is serialized with an exclusive lock on a sibling ".lock" file.
+type FileStore struct {
+	path string
+	aead cipher.AEAD
+	mux  sync.Mutex
+}
+
+// Get returns the stored token or nil when absent.
+func (s *FileStore) Get(ctx context.Context, key Key) (*oauth2.Token, error) {
+	var ret *oauth2.Token
+	err := s.transaction(ctx, false, func(records map[string]*record) error {
+		if r, ok := records[key.String()]; ok {
+			ret = r.token()
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// Put stores the token.
+func (s *FileStore) Put(ctx context.Context, key Key, token *oauth2.Token) error {
+	return s.transaction(ctx, true, func(records map[string]*record) error {
+		records[key.String()] = merge(records[key.String()], token)
+		return nil
+	})
+}
+
+// Delete removes the token.
+func (s *FileStore) Delete(ctx context.Context, key Key) error {
+	return s.transaction(ctx, true, func(records map[string]*record) error {
+		delete(records, key.String())
+		return nil
+	})
+}
+
+// Update atomically replaces the token with the result of fn while holding the file lock.
+func (s *FileStore) Update(ctx context.Context, key Key, fn UpdateFunc) (*oauth2.Token, error) {
+	var ret *oauth2.Token
+	err := s.transaction(ctx, true, func(records map[string]*record) error {
+		var current *oauth2.Token
+		previous := records[key.String()]
+		if previous != nil {
+			current = previous.token()
+		}
+		next, err := fn(current)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			delete(records, key.String())
+			return nil
+		}
+		updated := merge(previous, next)
+		records[key.String()] = updated
+		ret = updated.token()
+		return nil
+	})
+	return ret, err
+}
+
+// transaction loads records under the file lock, runs fn and, when write is set, saves the result.
+func (s *FileStore) transaction(ctx context.Context, write bool, fn func(records map[string]*record) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token store directory: %w", err)
+	}
+	unlock, err := lockFile(ctx, s.path+".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock token store: %w", err)
+	}
+	defer unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(records); err != nil {
+		return err
+	}
+	if !write {
+		return nil
+	}
+	return s.save(records)
+}
+
+func (s *FileStore) load() (map[string]*record, error) {
+	records := map[string]*record{}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("failed to read token store: %w", err)
+	}
+	if len(data) == 0 {
+		return records, nil
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("token store is corrupted")
+	}
+	plain, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(fileFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token store (wrong key?): %w", err)
+	}
+	if err := json.Unmarshal(plain, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode token store: %w", err)
+	}
+	return records, nil
+}
+
+func (s *FileStore) save(records map[string]*record) error {
+	plain, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data := s.aead.Seal(nonce, nonce, plain, []byte(fileFormat))
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	return nil
+}
+
+// NewFileStore creates a FileStore at path encrypting with key, which must be 16, 24 or 32 bytes
+// long to select AES-128, AES-192 or AES-256.
+func NewFileStore(path string, key []byte) (*FileStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token store key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{path: path, aead: aead}, nil
+}
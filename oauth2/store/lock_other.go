@@ -0,0 +1,74 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge is the age after which a lock file left by a crashed process is removed.
+const staleLockAge = time.Minute
+
+// lockFile acquires an exclusive lock by creating path, waiting until ctx is done. While held, the
+// lock file's modification time is refreshed so that a long transaction is not mistaken for a stale lock.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			done, stopped := make(chan struct{}), make(chan struct{})
+			go refreshLock(path, done, stopped)
+			return func() {
+				close(done)
+				<-stopped
+				_ = os.Remove(path)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			removeStaleLock(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// removeStaleLock moves the stale lock out of the way under a unique name before removing it, so that
+// of several waiters only one takes it over. A lock taken over by another waiter in the meantime is not
+// stale; it is linked back unless the path was locked again.
+func removeStaleLock(path string) {
+	moved := fmt.Sprintf("%s.%d.%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, moved); err != nil {
+		return
+	}
+	if info, err := os.Stat(moved); err == nil && time.Since(info.ModTime()) <= staleLockAge {
+		_ = os.Link(moved, path)
+	}
+	_ = os.Remove(moved)
+}
+
+// refreshLock touches the lock file until done is closed, then closes stopped.
+func refreshLock(path string, done, stopped chan struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(staleLockAge / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+		}
+	}
+}
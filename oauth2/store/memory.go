@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// MemoryStore is an in-process TokenStore.
+type MemoryStore struct {
+	records map[string]*record
+	mux     sync.Mutex
+}
+
+// Get returns the stored token or nil when absent.
+func (s *MemoryStore) Get(_ context.Context, key Key) (*oauth2.Token, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if r, ok := s.records[key.String()]; ok {
+		return r.token(), nil
+	}
+	return nil, nil
+}
+
+// Put stores the token.
+func (s *MemoryStore) Put(_ context.Context, key Key, token *oauth2.Token) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.records[key.String()] = merge(s.records[key.String()], token)
+	return nil
+}
+
+// Delete removes the token.
+func (s *MemoryStore) Delete(_ context.Context, key Key) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.records, key.String())
+	return nil
+}
+
+// Update atomically replaces the token with the result of fn.
+func (s *MemoryStore) Update(_ context.Context, key Key, fn UpdateFunc) (*oauth2.Token, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var current *oauth2.Token
+	previous := s.records[key.String()]
+	if previous != nil {
+		current = previous.token()
+	}
+	next, err := fn(current)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		delete(s.records, key.String())
+		return nil, nil
+	}
+	updated := merge(previous, next)
+	s.records[key.String()] = updated
+	return updated.token(), nil
+}
+
+// NewMemoryStore creates an in-process TokenStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]*record{}}
+}
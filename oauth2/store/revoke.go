@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/viant/mcp-protocol/oauth2/grant"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+)
+
+// ErrRevocationUnsupported is returned when the authorization server advertises no revocation_endpoint.
+var ErrRevocationUnsupported = errors.New("authorization server does not support token revocation")
+
+// Revoke revokes the stored refresh and access tokens at the issuer's revocation endpoint and removes
+// them from tokens. The entry is kept when revocation fails so that it can be retried, and removed
+// when the server does not support revocation.
+func Revoke(ctx context.Context, client *http.Client, server *meta.AuthorizationServerMetadata, auth grant.Authenticator, tokens TokenStore, key Key) error {
+	token, err := tokens.Get(ctx, key)
+	if err != nil || token == nil {
+		return err
+	}
+	if server == nil || server.RevocationEndpoint == "" {
+		if err := tokens.Delete(ctx, key); err != nil {
+			return err
+		}
+		return ErrRevocationUnsupported
+	}
+	// revoking the refresh token first also invalidates access tokens derived from it (RFC 7009 §2.1)
+	if token.RefreshToken != "" {
+		if err := RevokeToken(ctx, client, server.RevocationEndpoint, token.RefreshToken, "refresh_token", auth); err != nil {
+			return err
+		}
+	}
+	if err := RevokeToken(ctx, client, server.RevocationEndpoint, token.AccessToken, "access_token", auth); err != nil {
+		return err
+	}
+	return tokens.Delete(ctx, key)
+}
+
+// RevokeToken sends a revocation request for a single token (RFC 7009 §2.1).
+func RevokeToken(ctx context.Context, client *http.Client, endpoint, token, tokenTypeHint string, auth grant.Authenticator) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	params := url.Values{"token": {token}}
+	if tokenTypeHint != "" {
+		params.Set("token_type_hint", tokenTypeHint)
+	}
+	header := http.Header{}
+	if auth != nil {
+		if err := auth.Authenticate(ctx, endpoint, header, params); err != nil {
+			return fmt.Errorf("failed to authenticate client: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("revocation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	ret := &grant.Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if json.Unmarshal(data, ret) != nil || ret.Code == "" {
+		ret.Code = http.StatusText(resp.StatusCode)
+	}
+	return ret
+}
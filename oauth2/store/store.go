@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Key identifies a stored token.
+type Key struct {
+	Issuer   string `json:"issuer"`
+	Resource string `json:"resource"`
+	Scope    string `json:"scope,omitempty"`
+	// AuthorizationDetails is the encoded authorization_details request parameter (RFC 9396), if any.
+	AuthorizationDetails string `json:"authorization_details,omitempty"`
+}
+
+// String returns the canonical form of the key.
+func (k Key) String() string {
+	ret := k.Issuer + "\n" + k.Resource + "\n" + k.Scope
+	if k.AuthorizationDetails != "" {
+		ret += "\n" + k.AuthorizationDetails
+	}
+	return ret
+}
+
+// UpdateFunc computes a new token from the currently stored one (nil when absent).
+// Returning a nil token deletes the entry.
+type UpdateFunc func(current *oauth2.Token) (*oauth2.Token, error)
+
+// TokenStore persists tokens keyed by issuer, resource and scope.
+type TokenStore interface {
+	// Get returns the stored token or nil when absent.
+	Get(ctx context.Context, key Key) (*oauth2.Token, error)
+	// Put stores the token. When the token carries no refresh token, a stored refresh token is kept.
+	Put(ctx context.Context, key Key, token *oauth2.Token) error
+	// Delete removes the token.
+	Delete(ctx context.Context, key Key) error
+	// Update atomically replaces the token with the result of fn, which runs while the entry is locked,
+	// so that concurrent refreshes do not reuse a rotated refresh token.
+	Update(ctx context.Context, key Key, fn UpdateFunc) (*oauth2.Token, error)
+}
+
+// record is the serialized form of a token, retaining extension members such as id_token.
+type record struct {
+	AccessToken  string         `json:"access_token"`
+	TokenType    string         `json:"token_type,omitempty"`
+	RefreshToken string         `json:"refresh_token,omitempty"`
+	Expiry       time.Time      `json:"expiry,omitempty"`
+	Extra        map[string]any `json:"extra,omitempty"`
+}
+
+// extraMembers lists token response members retained across serialization.
+var extraMembers = []string{"id_token", "scope", "issued_token_type", "authorization_details"}
+
+func newRecord(token *oauth2.Token) *record {
+	ret := &record{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry,
+	}
+	for _, member := range extraMembers {
+		if value := token.Extra(member); value != nil {
+			if ret.Extra == nil {
+				ret.Extra = map[string]any{}
+			}
+			ret.Extra[member] = value
+		}
+	}
+	return ret
+}
+
+func (r *record) token() *oauth2.Token {
+	ret := &oauth2.Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		RefreshToken: r.RefreshToken,
+		Expiry:       r.Expiry,
+	}
+	if len(r.Extra) > 0 {
+		return ret.WithExtra(r.Extra)
+	}
+	return ret
+}
+
+// merge applies refresh-token rotation rules: a token issued without a refresh token keeps the previous one.
+func merge(previous *record, next *oauth2.Token) *record {
+	ret := newRecord(next)
+	if previous != nil && ret.RefreshToken == "" {
+		ret.RefreshToken = previous.RefreshToken
+	}
+	return ret
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/mcp-protocol/oauth2/meta"
+	"golang.org/x/oauth2"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tokens.bin")
+	key := Key{Issuer: "https://as.example.com", Resource: "https://mcp.example.com", Scope: "read"}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	tokens, err := NewFileStore(path, secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	token := (&oauth2.Token{AccessToken: "at1", RefreshToken: "rt1", Expiry: time.Now().Add(time.Hour)}).WithExtra(map[string]any{"id_token": "idt"})
+	assert.NoError(t, tokens.Put(ctx, key, token))
+
+	reopened, _ := NewFileStore(path, secret)
+	actual, err := reopened.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "at1", actual.AccessToken)
+	assert.Equal(t, "idt", actual.Extra("id_token"))
+
+	// refresh without rotation keeps the refresh token
+	actual, err = reopened.Update(ctx, key, func(current *oauth2.Token) (*oauth2.Token, error) {
+		assert.Equal(t, "rt1", current.RefreshToken)
+		return &oauth2.Token{AccessToken: "at2"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "rt1", actual.RefreshToken)
+
+	// rotation replaces the refresh token
+	assert.NoError(t, tokens.Put(ctx, key, &oauth2.Token{AccessToken: "at3", RefreshToken: "rt2"}))
+	actual, _ = tokens.Get(ctx, key)
+	assert.Equal(t, "rt2", actual.RefreshToken)
+
+	wrongKey, _ := NewFileStore(path, []byte("fedcba9876543210fedcba9876543210"))
+	_, err = wrongKey.Get(ctx, key)
+	assert.Error(t, err)
+
+	assert.NoError(t, tokens.Delete(ctx, key))
+	actual, err = tokens.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Nil(t, actual)
+}
+
+func TestRevoke(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusServiceUnavailable
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if status == http.StatusOK {
+			revoked = append(revoked, r.PostForm.Get("token"))
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	key := Key{Issuer: server.URL, Resource: "https://mcp.example.com"}
+	tokens := NewMemoryStore()
+	assert.NoError(t, tokens.Put(ctx, key, &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}))
+	metadata := &meta.AuthorizationServerMetadata{Issuer: server.URL, RevocationEndpoint: server.URL + "/revoke"}
+
+	// a failed revocation keeps the entry for a retry
+	assert.Error(t, Revoke(ctx, server.Client(), metadata, nil, tokens, key))
+	actual, _ := tokens.Get(ctx, key)
+	assert.NotNil(t, actual)
+
+	status = http.StatusOK
+	assert.NoError(t, Revoke(ctx, server.Client(), metadata, nil, tokens, key))
+	assert.Equal(t, []string{"rt", "at"}, revoked)
+	actual, _ = tokens.Get(ctx, key)
+	assert.Nil(t, actual)
+
+	assert.NoError(t, tokens.Put(ctx, key, &oauth2.Token{AccessToken: "at"}))
+	assert.ErrorIs(t, Revoke(ctx, server.Client(), &meta.AuthorizationServerMetadata{}, nil, tokens, key), ErrRevocationUnsupported)
+	actual, _ = tokens.Get(ctx, key)
+	assert.Nil(t, actual)
+}
@@ -0,0 +1,638 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// DecodeOption customizes DecodeArguments.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	strict   bool
+	defaults bool
+}
+
+// WithUnknownFieldsRejected rejects argument members that do not match any field of the target struct.
+func WithUnknownFieldsRejected() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// WithDefaults sets struct fields missing from the arguments to the value of their `default:"..."` tag.
+func WithDefaults() DecodeOption {
+	return func(o *decodeOptions) {
+		o.defaults = true
+	}
+}
+
+// ArgumentError reports an argument that cannot be decoded, with its path such as "filters[1].value".
+type ArgumentError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path prefixed error message.
+func (e *ArgumentError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeArguments decodes tool call arguments, as unmarshalled by encoding/json, directly into v
+// without a JSON round trip, honoring json tags, type overrides and registered interfaces.
+// Failures are reported as *ArgumentError.
+func DecodeArguments(arguments map[string]interface{}, v any, opts ...DecodeOption) error {
+	options := &decodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer, got %T", v)
+	}
+	if arguments == nil {
+		if !options.defaults {
+			return nil
+		}
+		arguments = map[string]interface{}{}
+	}
+	return options.decode(arguments, rv.Elem())
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	emptyInterfaceType  = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+func (o *decodeOptions) decode(value interface{}, v reflect.Value) error {
+	t := v.Type()
+	if value == nil {
+		if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface || t.Kind() == reflect.Map || t.Kind() == reflect.Slice {
+			v.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+	if override, ok := LookupType(t); ok && override.Decode != nil {
+		return o.decodeJSON(value, v, override.Decode)
+	}
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		// as in encoding/json, json.Unmarshaler takes precedence over encoding.TextUnmarshaler
+		if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+			return o.decodeJSON(value, v, func(data []byte, v reflect.Value) error {
+				return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
+			})
+		}
+		if text, ok := value.(string); ok && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+				return &ArgumentError{Err: err}
+			}
+			return nil
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return o.decode(value, v.Elem())
+	case reflect.Interface:
+		return o.decodeInterface(value, v)
+	case reflect.Struct:
+		members, ok := value.(map[string]interface{})
+		if !ok {
+			return typeError("object", value)
+		}
+		return o.decodeStruct(members, v)
+	case reflect.Map:
+		return o.decodeMap(value, v)
+	case reflect.Slice, reflect.Array:
+		return o.decodeList(value, v)
+	case reflect.String:
+		text, ok := value.(string)
+		if !ok {
+			return typeError("string", value)
+		}
+		v.SetString(text)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return typeError("boolean", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := integerArgument(value)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(n) {
+			return &ArgumentError{Err: fmt.Errorf("%d overflows %s", n, t)}
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := unsignedArgument(value)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(n) {
+			return &ArgumentError{Err: fmt.Errorf("%d overflows %s", n, t)}
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, ok := numberArgument(value)
+		if !ok {
+			return typeError("number", value)
+		}
+		if v.OverflowFloat(f) {
+			return &ArgumentError{Err: fmt.Errorf("%v overflows %s", f, t)}
+		}
+		v.SetFloat(f)
+	default:
+		return &ArgumentError{Err: fmt.Errorf("unsupported type %s", t)}
+	}
+	return nil
+}
+
+// decodeJSON marshals just this value for decoders that need JSON, e.g. json.Unmarshaler implementations.
+func (o *decodeOptions) decodeJSON(value interface{}, v reflect.Value, decode func(data []byte, v reflect.Value) error) error {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = decode(data, v)
+	}
+	if err != nil {
+		return &ArgumentError{Err: err}
+	}
+	return nil
+}
+
+func (o *decodeOptions) decodeInterface(value interface{}, v reflect.Value) error {
+	p, ok := LookupInterface(v.Type())
+	if !ok {
+		if v.Type() == emptyInterfaceType || reflect.TypeOf(value).AssignableTo(v.Type()) {
+			v.Set(reflect.ValueOf(value))
+			return nil
+		}
+		return &ArgumentError{Err: fmt.Errorf("cannot decode into unregistered interface %s", v.Type())}
+	}
+	members, ok := value.(map[string]interface{})
+	if !ok {
+		return typeError("object", value)
+	}
+	if p.Discriminator != "" {
+		discriminator, _ := members[p.Discriminator].(string)
+		for _, variant := range p.Variants {
+			if variant.Value == discriminator {
+				return o.decodeVariant(members, v, variant, p.Discriminator)
+			}
+		}
+		if discriminator == "" {
+			return &ArgumentError{Path: p.Discriminator, Err: fmt.Errorf("missing discriminator for %s", p.Interface)}
+		}
+		return &ArgumentError{Path: p.Discriminator, Err: fmt.Errorf("unknown value %q for %s", discriminator, p.Interface)}
+	}
+	strict := *o
+	strict.strict = true
+	for _, variant := range p.Variants {
+		if strict.decodeVariant(members, v, variant, "") == nil {
+			return nil
+		}
+	}
+	return &ArgumentError{Err: fmt.Errorf("value does not match any implementation of %s", p.Interface)}
+}
+
+// decodeVariant decodes members into variant and stores it in v; a discriminator without a matching field is skipped.
+func (o *decodeOptions) decodeVariant(members map[string]interface{}, v reflect.Value, variant Variant, discriminator string) error {
+	target := reflect.New(structType(variant.Type))
+	if discriminator != "" && matchField(fieldsOf(target.Type().Elem()), discriminator) < 0 {
+		filtered := make(map[string]interface{}, len(members))
+		for k, member := range members {
+			if k != discriminator {
+				filtered[k] = member
+			}
+		}
+		members = filtered
+	}
+	if err := o.decodeStruct(members, target.Elem()); err != nil {
+		return err
+	}
+	if variant.Type.Kind() == reflect.Ptr {
+		v.Set(target)
+	} else {
+		v.Set(target.Elem())
+	}
+	return nil
+}
+
+// argumentField is a struct field addressed by its JSON member name.
+type argumentField struct {
+	name   string
+	index  []int
+	field  reflect.StructField
+	quoted bool
+	tagged bool
+}
+
+var argumentFields = syncmap.NewMap[reflect.Type, []argumentField]()
+
+// fieldsOf returns the decodable fields of struct type t, with embedded struct fields flattened as in schema generation.
+// Conflicting names are resolved as in encoding/json: the shallowest field wins, then the only one with a JSON tag name;
+// otherwise the name is ambiguous and none of them is decoded.
+func fieldsOf(t reflect.Type) []argumentField {
+	if ret, ok := argumentFields.Get(t); ok {
+		return ret
+	}
+	type embeddedStruct struct {
+		t     reflect.Type
+		index []int
+	}
+	var candidates [][]argumentField // per depth
+	visited := map[reflect.Type]bool{}
+	for current := []embeddedStruct{{t: t}}; len(current) > 0; {
+		var next []embeddedStruct
+		var level []argumentField
+		for _, item := range current {
+			if visited[item.t] {
+				continue
+			}
+			for i := 0; i < item.t.NumField(); i++ {
+				field := item.t.Field(i)
+				tag := field.Tag.Get("json")
+				if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+				index := append(append([]int{}, item.index...), i)
+				if isEmbedded(field) {
+					if !field.IsExported() && field.Type.Kind() == reflect.Ptr {
+						// encoding/json cannot allocate pointers to unexported embedded structs either
+						continue
+					}
+					next = append(next, embeddedStruct{t: structType(field.Type), index: index})
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = field.Name
+				}
+				level = append(level, argumentField{name: name, index: index, field: field, quoted: quoted(field.Type, options), tagged: tagged})
+			}
+		}
+		// a type embedded more than once at the same depth contributes its fields once per embedding
+		for _, item := range current {
+			visited[item.t] = true
+		}
+		candidates = append(candidates, level)
+		current = next
+	}
+
+	var ret []argumentField
+	resolved := map[string]bool{}
+	for _, level := range candidates {
+		for _, field := range level {
+			if resolved[field.name] {
+				continue
+			}
+			resolved[field.name] = true
+			if dominant, ok := dominantField(level, field.name); ok {
+				ret = append(ret, dominant)
+			}
+		}
+	}
+	slices.SortFunc(ret, func(a, b argumentField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	argumentFields.Put(t, ret)
+	return ret
+}
+
+// dominantField returns the field named name among fields of one depth, if it is not ambiguous.
+func dominantField(fields []argumentField, name string) (argumentField, bool) {
+	var ret argumentField
+	count, tagged := 0, 0
+	for _, field := range fields {
+		if field.name != name {
+			continue
+		}
+		count++
+		if field.tagged {
+			if tagged++; tagged == 1 {
+				ret = field
+			}
+		} else if count == 1 {
+			ret = field
+		}
+	}
+	if count == 1 || tagged == 1 {
+		return ret, true
+	}
+	return argumentField{}, false
+}
+
+func (o *decodeOptions) decodeStruct(members map[string]interface{}, v reflect.Value) error {
+	fields := fieldsOf(v.Type())
+	var matched []bool
+	if o.defaults {
+		matched = make([]bool, len(fields))
+	}
+	var err error
+	for key, value := range members {
+		i := matchField(fields, key)
+		if i < 0 {
+			if o.strict {
+				return &ArgumentError{Path: key, Err: errors.New("unknown field")}
+			}
+			continue
+		}
+		name := fields[i].name
+		if name != key {
+			if _, ok := members[name]; ok {
+				// an exact match takes precedence over a case-insensitive one
+				continue
+			}
+		}
+		if matched != nil {
+			matched[i] = true
+		}
+		if fields[i].quoted {
+			if value, err = unquote(value); err != nil {
+				return prefixError(err, name)
+			}
+		}
+		if err = o.decode(value, fieldByIndex(v, fields[i].index)); err != nil {
+			return prefixError(err, name)
+		}
+	}
+	for i, field := range fields {
+		if matched == nil || matched[i] {
+			continue
+		}
+		def, ok := field.field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		if err := o.decode(defaultArgument(def, field.field.Type), fieldByIndex(v, field.index)); err != nil {
+			return &ArgumentError{Path: field.name, Err: fmt.Errorf("invalid default %q: %w", def, err)}
+		}
+	}
+	return nil
+}
+
+// quoted reports whether the json tag options hold "string" for a field encoding/json reads
+// from a JSON-encoded string: string, boolean and numeric fields, or pointers to them.
+func quoted(t reflect.Type, options string) bool {
+	found := false
+	for _, option := range strings.Split(options, ",") {
+		found = found || option == "string"
+	}
+	if !found {
+		return false
+	}
+	switch structType(t).Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// unquote returns the value held in the JSON-encoded string of a `json:",string"` field.
+func unquote(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return nil, typeError("string", value)
+	}
+	var ret interface{}
+	if err := json.Unmarshal([]byte(text), &ret); err != nil {
+		return nil, &ArgumentError{Err: fmt.Errorf("invalid quoted value %q", text)}
+	}
+	switch ret.(type) {
+	case nil, string, bool, float64:
+		return ret, nil
+	}
+	return nil, &ArgumentError{Err: fmt.Errorf("invalid quoted value %q", text)}
+}
+
+// matchField returns the index of the field named key, preferring an exact match over a case-insensitive one.
+func matchField(fields []argumentField, key string) int {
+	ret := -1
+	for i, field := range fields {
+		if field.name == key {
+			return i
+		}
+		if ret < 0 && strings.EqualFold(field.name, key) {
+			ret = i
+		}
+	}
+	return ret
+}
+
+// fieldByIndex returns the nested field, allocating nil embedded struct pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// defaultArgument converts a default tag into an argument value: verbatim for strings, otherwise parsed as JSON.
+func defaultArgument(def string, t reflect.Type) interface{} {
+	if structType(t).Kind() == reflect.String {
+		return def
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(def), &value); err != nil {
+		return def
+	}
+	return value
+}
+
+func (o *decodeOptions) decodeMap(value interface{}, v reflect.Value) error {
+	members, ok := value.(map[string]interface{})
+	if !ok {
+		return typeError("object", value)
+	}
+	t := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, len(members)))
+	}
+	for key, member := range members {
+		keyValue := reflect.New(t.Key()).Elem()
+		switch {
+		case reflect.PointerTo(t.Key()).Implements(textUnmarshalerType):
+			if err := keyValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+				return &ArgumentError{Path: key, Err: err}
+			}
+		case t.Key().Kind() == reflect.String:
+			keyValue.SetString(key)
+		default:
+			n, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return &ArgumentError{Path: key, Err: fmt.Errorf("invalid %s key", t.Key())}
+			}
+			if err = o.decode(n, keyValue); err != nil {
+				return prefixError(err, key)
+			}
+		}
+		item := reflect.New(t.Elem()).Elem()
+		if err := o.decode(member, item); err != nil {
+			return prefixError(err, key)
+		}
+		v.SetMapIndex(keyValue, item)
+	}
+	return nil
+}
+
+func (o *decodeOptions) decodeList(value interface{}, v reflect.Value) error {
+	t := v.Type()
+	if text, ok := value.(string); ok && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return &ArgumentError{Err: err}
+		}
+		v.SetBytes(data)
+		return nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return typeError("array", value)
+	}
+	if t.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(t, len(items), len(items)))
+	} else if len(items) > v.Len() {
+		return &ArgumentError{Err: fmt.Errorf("expected at most %d items, got %d", v.Len(), len(items))}
+	}
+	for i, item := range items {
+		if err := o.decode(item, v.Index(i)); err != nil {
+			return prefixError(err, "["+strconv.Itoa(i)+"]")
+		}
+	}
+	return nil
+}
+
+func numberArgument(value interface{}) (float64, bool) {
+	switch actual := value.(type) {
+	case float64:
+		return actual, true
+	case json.Number:
+		f, err := actual.Float64()
+		return f, err == nil
+	case int:
+		return float64(actual), true
+	case int64:
+		return float64(actual), true
+	}
+	return 0, false
+}
+
+func integerArgument(value interface{}) (int64, error) {
+	switch actual := value.(type) {
+	case int:
+		return int64(actual), nil
+	case int64:
+		return actual, nil
+	case json.Number:
+		if n, err := actual.Int64(); err == nil {
+			return n, nil
+		}
+	}
+	f, ok := numberArgument(value)
+	if !ok {
+		return 0, typeError("integer", value)
+	}
+	if f != math.Trunc(f) {
+		return 0, &ArgumentError{Err: fmt.Errorf("expected integer, got %v", f)}
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, &ArgumentError{Err: fmt.Errorf("%v overflows int64", f)}
+	}
+	return int64(f), nil
+}
+
+// unsignedArgument returns a non-negative integer argument. Values above 2^53 are exact only as
+// json.Number, e.g. when arguments are decoded with json.Decoder.UseNumber.
+func unsignedArgument(value interface{}) (uint64, error) {
+	if number, ok := value.(json.Number); ok {
+		if n, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+			return n, nil
+		}
+	}
+	f, ok := numberArgument(value)
+	if !ok {
+		return 0, typeError("integer", value)
+	}
+	if f != math.Trunc(f) {
+		return 0, &ArgumentError{Err: fmt.Errorf("expected integer, got %v", f)}
+	}
+	if f < 0 || f >= math.MaxUint64 {
+		return 0, &ArgumentError{Err: fmt.Errorf("%v overflows uint64", f)}
+	}
+	return uint64(f), nil
+}
+
+func typeError(expected string, value interface{}) error {
+	return &ArgumentError{Err: fmt.Errorf("expected %s, got %s", expected, jsonKind(value))}
+}
+
+// jsonKind names the JSON type of a decoded value.
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number, int, int64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// prefixError prepends a member name or "[index]" segment to the path of an argument error,
+// building paths only on failure.
+func prefixError(err error, segment string) error {
+	argumentErr, ok := err.(*ArgumentError)
+	if !ok {
+		return &ArgumentError{Path: segment, Err: err}
+	}
+	switch {
+	case argumentErr.Path == "":
+		argumentErr.Path = segment
+	case strings.HasPrefix(argumentErr.Path, "["):
+		argumentErr.Path = segment + argumentErr.Path
+	default:
+		argumentErr.Path = segment + "." + argumentErr.Path
+	}
+	return argumentErr
+}
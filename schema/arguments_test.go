@@ -0,0 +1,254 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// bothUnmarshalers decodes differently as JSON and as text, exposing which one was used.
+type bothUnmarshalers struct {
+	Source string
+}
+
+func (b *bothUnmarshalers) UnmarshalJSON(data []byte) error {
+	b.Source = "json:" + string(data)
+	return nil
+}
+
+func (b *bothUnmarshalers) UnmarshalText(text []byte) error {
+	b.Source = "text:" + string(text)
+	return nil
+}
+
+type deepFields struct {
+	Level string `json:"level"`
+}
+
+type conflictA struct {
+	deepFields
+	Name string
+}
+
+type conflictB struct {
+	Name string
+	Pick string `json:"Pick"`
+}
+
+type conflictC struct {
+	Pick  string
+	Level string `json:"level"`
+}
+
+// conflictInput has ambiguous (Name), tagged (Pick) and shallower (level) embedded fields.
+type conflictInput struct {
+	conflictA
+	conflictB
+	conflictC
+	Big   uint64           `json:"big"`
+	Small int64            `json:"small"`
+	Both  bothUnmarshalers `json:"both"`
+	Count int              `json:"count,string"`
+}
+
+// Test: DecodeArguments decodes like a json.Marshal and json.Unmarshal round trip.
+func TestDecodeArguments_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      string
+		useNumber bool
+	}{
+		{name: "embedded conflicts", data: `{"Name":"n","Pick":"p","level":"l","count":"7"}`},
+		{name: "unmarshaler precedence", data: `{"both":"x"}`},
+		{name: "uint64 above MaxInt64", data: `{"big":18446744073709551615,"small":-9223372036854775808}`, useNumber: true},
+		{name: "exact float", data: `{"big":9223372036854775808,"small":-4611686018427387904}`},
+	}
+	for _, tc := range testCases {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(tc.data)))
+		if tc.useNumber {
+			decoder.UseNumber()
+		}
+		var arguments map[string]interface{}
+		if err := decoder.Decode(&arguments); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var expected, actual conflictInput
+		if err := json.Unmarshal([]byte(tc.data), &expected); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if err := DecodeArguments(arguments, &actual); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("%s: expected %+v, got %+v", tc.name, expected, actual)
+		}
+	}
+}
+
+// Test: integers out of range of the target type are rejected.
+func TestDecodeArguments_Overflow(t *testing.T) {
+	for _, arguments := range []map[string]interface{}{
+		{"big": -1.0},
+		{"big": 1.8446744073709552e19},
+		{"small": 9.223372036854775807e18},
+		{"big": json.Number("18446744073709551616")},
+	} {
+		var input conflictInput
+		if err := DecodeArguments(arguments, &input); err == nil {
+			t.Fatalf("expected overflow error for %v, got %+v", arguments, input)
+		}
+	}
+}
+
+type argumentFilter struct {
+	Field string  `json:"field"`
+	Value float64 `json:"value"`
+}
+
+type argumentPage struct {
+	Limit  int `json:"limit" default:"20"`
+	Offset int `json:"offset,omitempty"`
+}
+
+type argumentInput struct {
+	argumentPage
+	Query   string            `json:"query"`
+	Mode    string            `json:"mode,omitempty" default:"fast"`
+	Strict  *bool             `json:"strict,omitempty" default:"true"`
+	Filters []argumentFilter  `json:"filters,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Timeout time.Duration     `json:"timeout,omitempty"`
+	When    time.Time         `json:"when,omitempty"`
+	Shape   shape             `json:"shape,omitempty"`
+	Any     interface{}       `json:"any,omitempty"`
+}
+
+// Test: arguments decode directly into the input with defaults, strict fields and error paths.
+func TestDecodeArguments(t *testing.T) {
+	registerShapes(t)
+	arguments := map[string]interface{}{
+		"query":   "q",
+		"QUERY":   "ignored by exact match precedence",
+		"offset":  float64(5),
+		"filters": []interface{}{map[string]interface{}{"field": "a", "value": 1.5}},
+		"labels":  map[string]interface{}{"k": "v"},
+		"timeout": "2s",
+		"when":    "2024-01-02T03:04:05Z",
+		"shape":   map[string]interface{}{"kind": "rect", "width": float64(2), "height": float64(3)},
+		"any":     []interface{}{"x"},
+	}
+	var input argumentInput
+	if err := DecodeArguments(arguments, &input, WithDefaults()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Query != "q" || input.Offset != 5 || input.Limit != 20 || input.Mode != "fast" || input.Strict == nil || !*input.Strict {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+	if input.Filters[0].Value != 1.5 || input.Labels["k"] != "v" || input.Timeout != 2*time.Second || input.When.Year() != 2024 {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+	if r, ok := input.Shape.(*rect); !ok || r.area() != 6 || !reflect.DeepEqual(input.Any, []interface{}{"x"}) {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+
+	// a discriminator without a matching field is not an unknown field
+	input = argumentInput{}
+	if err := DecodeArguments(map[string]interface{}{"shape": arguments["shape"]}, &input, WithUnknownFieldsRejected()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Limit != 0 {
+		t.Fatalf("expected no defaults without WithDefaults, got: %v", input.Limit)
+	}
+
+	testCases := []struct {
+		name      string
+		arguments map[string]interface{}
+		options   []DecodeOption
+		path      string
+		message   string
+	}{
+		{name: "type", arguments: map[string]interface{}{"filters": []interface{}{map[string]interface{}{}, map[string]interface{}{"value": "x"}}}, path: "filters[1].value", message: "filters[1].value: expected number, got string"},
+		{name: "integer", arguments: map[string]interface{}{"limit": 1.5}, path: "limit", message: "limit: expected integer, got 1.5"},
+		{name: "unknown", arguments: map[string]interface{}{"filters": []interface{}{map[string]interface{}{"other": 1.0}}}, options: []DecodeOption{WithUnknownFieldsRejected()}, path: "filters[0].other", message: "filters[0].other: unknown field"},
+		{name: "discriminator", arguments: map[string]interface{}{"shape": map[string]interface{}{"kind": "square"}}, path: "shape.kind"},
+		{name: "override", arguments: map[string]interface{}{"timeout": "soon"}, path: "timeout"},
+	}
+	for _, tc := range testCases {
+		input = argumentInput{}
+		err := DecodeArguments(tc.arguments, &input, tc.options...)
+		var argumentErr *ArgumentError
+		if !errors.As(err, &argumentErr) || argumentErr.Path != tc.path {
+			t.Fatalf("%s: expected error at %s, got: %v", tc.name, tc.path, err)
+		}
+		if tc.message != "" && err.Error() != tc.message {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.message, err.Error())
+		}
+	}
+}
+
+// Test: `json:",string"` fields decode from JSON-encoded strings as with encoding/json.
+func TestDecodeArguments_QuotedFields(t *testing.T) {
+	type quotedInput struct {
+		ID      int64    `json:"id,string"`
+		Enabled *bool    `json:"enabled,string,omitempty"`
+		Ratio   float64  `json:",string"`
+		Name    string   `json:"name,string"`
+		Dash    string   `json:"-,"`
+		Skipped string   `json:"-"`
+		Tags    []string `json:"tags,string"`
+	}
+	arguments := map[string]interface{}{"id": "42", "enabled": "true", "Ratio": "0.5", "name": `"x"`, "-": "dash", "tags": []interface{}{"a"}}
+	var expected quotedInput
+	data, _ := json.Marshal(arguments)
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("unexpected encoding/json error: %v", err)
+	}
+	var input quotedInput
+	if err := DecodeArguments(arguments, &input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(input, expected) || input.ID != 42 || !*input.Enabled || input.Dash != "dash" {
+		t.Fatalf("expected %#v, got: %#v", expected, input)
+	}
+	err := DecodeArguments(map[string]interface{}{"id": float64(42)}, &input)
+	if err == nil || err.Error() != "id: expected string, got number" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func benchmarkArguments(size int) map[string]interface{} {
+	filters := make([]interface{}, size)
+	for i := range filters {
+		filters[i] = map[string]interface{}{"field": fmt.Sprintf("f%d", i), "value": float64(i)}
+	}
+	return map[string]interface{}{"query": "q", "limit": float64(10), "filters": filters}
+}
+
+// BenchmarkDecodeArguments_RoundTrip decodes via json.Marshal and json.Unmarshal, as RegisterTool used to.
+func BenchmarkDecodeArguments_RoundTrip(b *testing.B) {
+	arguments := benchmarkArguments(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var input argumentInput
+		data, _ := json.Marshal(arguments)
+		if err := json.Unmarshal(data, &input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeArguments_Direct decodes the same arguments with DecodeArguments.
+func BenchmarkDecodeArguments_Direct(b *testing.B) {
+	arguments := benchmarkArguments(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var input argumentInput
+		if err := DecodeArguments(arguments, &input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
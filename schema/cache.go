@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// registryVersion changes whenever a type override, interface or description registration
+// may alter generated schemas, so that cached entries of earlier versions are no longer used.
+var registryVersion atomic.Uint64
+
+// schemaKey identifies a generated schema by type and the comparable generation options.
+type schemaKey struct {
+	t                  reflect.Type
+	definitions        DefinitionsMode
+	profile            Profile
+	sourceDescriptions bool
+	version            uint64
+}
+
+// schemaEntry is a cached generation result; callers receive copies.
+type schemaEntry struct {
+	properties ToolInputSchemaProperties
+	required   []string
+	defs       map[string]map[string]interface{}
+	err        error
+}
+
+// schemaCache holds the schemas generated for the current registry version; it is replaced on invalidation.
+var schemaCache atomic.Pointer[syncmap.Map[schemaKey, *schemaEntry]]
+
+func init() {
+	schemaCache.Store(syncmap.NewMap[schemaKey, *schemaEntry]())
+}
+
+// invalidateCache makes schemas generated so far stale and drops them.
+func invalidateCache() {
+	registryVersion.Add(1)
+	schemaCache.Store(syncmap.NewMap[schemaKey, *schemaEntry]())
+}
+
+// cachedSchema returns the cached generation result for key.
+func cachedSchema(key schemaKey) (*schemaEntry, bool) {
+	return schemaCache.Load().Get(key)
+}
+
+// cacheSchema stores a generation result unless a registration made it stale meanwhile.
+func cacheSchema(key schemaKey, entry *schemaEntry) {
+	cache := schemaCache.Load()
+	if key.version == registryVersion.Load() {
+		cache.Put(key, entry)
+	}
+}
+
+// cacheKey returns the cache key of a generation, or false when options hold hooks, which cannot be compared.
+func (o *structToPropertiesOptions) cacheKey(t reflect.Type) (schemaKey, bool) {
+	if o.SkipFieldHook != nil || o.IsRequiredHook != nil || o.FormatHook != nil || o.NullableHook != nil || o.DescriptionHook != nil {
+		return schemaKey{}, false
+	}
+	return schemaKey{
+		t:                  t,
+		definitions:        o.Definitions,
+		profile:            o.Profile,
+		sourceDescriptions: o.SourceDescriptions,
+		version:            registryVersion.Load(),
+	}, true
+}
+
+func newSchemaEntry(properties ToolInputSchemaProperties, required []string, defs map[string]map[string]interface{}, err error) *schemaEntry {
+	ret := &schemaEntry{properties: copyProperties(properties), required: append([]string(nil), required...), err: err}
+	if defs != nil {
+		ret.defs = copyProperties(defs)
+	}
+	return ret
+}
+
+// get returns a copy of the cached result so that callers can amend it.
+func (e *schemaEntry) get() (ToolInputSchemaProperties, []string, map[string]map[string]interface{}, error) {
+	var defs map[string]map[string]interface{}
+	if e.defs != nil {
+		defs = copyProperties(e.defs)
+	}
+	return copyProperties(e.properties), append([]string(nil), e.required...), defs, e.err
+}
+
+func copyProperties(properties map[string]map[string]interface{}) ToolInputSchemaProperties {
+	ret := make(ToolInputSchemaProperties, len(properties))
+	for name, property := range properties {
+		ret[name] = copySchema(property)
+	}
+	return ret
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test: cached schemas are copies and registrations invalidate them.
+func TestStructToSchema_Cache(t *testing.T) {
+	type cachedInput struct {
+		Name  string      `json:"name" choice:"a" choice:"b"`
+		Limit *int        `json:"limit,omitempty"`
+		Wait  cachedDelay `json:"wait"`
+	}
+	props, _, _ := StructToSchema(reflect.TypeOf(cachedInput{}))
+	prop(props, "name")["enum"].([]string)[0] = "mutated"
+	props["extra"] = map[string]interface{}{}
+	props, _, _ = StructToSchema(reflect.TypeOf(cachedInput{}))
+	if prop(props, "name")["enum"].([]string)[0] != "a" || props["extra"] != nil {
+		t.Fatalf("expected cached schema to be copied, got: %#v", props)
+	}
+	if prop(props, "wait")["type"] != "integer" {
+		t.Fatalf("unexpected wait schema: %#v", prop(props, "wait"))
+	}
+	RegisterType[cachedDelay](TypeOverride{Schema: map[string]interface{}{"type": "string"}})
+	if size := schemaCache.Load().Size(); size != 0 {
+		t.Fatalf("expected registration to drop cached schemas, got %d", size)
+	}
+	props, _, _ = StructToSchema(reflect.TypeOf(cachedInput{}))
+	if prop(props, "wait")["type"] != "string" {
+		t.Fatalf("expected registration to invalidate the cache, got: %#v", prop(props, "wait"))
+	}
+	UnregisterType[cachedDelay]()
+	props, _, _ = StructToSchema(reflect.TypeOf(cachedInput{}))
+	if prop(props, "wait")["type"] != "integer" {
+		t.Fatalf("expected unregistration to invalidate the cache, got: %#v", prop(props, "wait"))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s ToolInputSchema
+			if err := s.Load(searchInput{}, WithProfile(ProfileStrict)); err != nil || s.Defs["treeNode"] == nil {
+				t.Errorf("unexpected schema: %#v %v", s, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type cachedDelay int
+
+// toolInputTypes returns n distinct input struct types, standing in for the tools of a large server.
+func toolInputTypes(n int) []reflect.Type {
+	var ret []reflect.Type
+	for i := 0; i < n; i++ {
+		ret = append(ret, reflect.StructOf([]reflect.StructField{
+			{Name: "Query", Type: reflect.TypeOf(""), Tag: `json:"query" description:"search query" minLength:"1"`},
+			{Name: "Limit", Type: reflect.TypeOf((*int)(nil)), Tag: `json:"limit,omitempty" minimum:"1" maximum:"100"`},
+			{Name: "Filters", Type: reflect.TypeOf([]filter{}), Tag: `json:"filters,omitempty"`},
+			{Name: "Root", Type: reflect.TypeOf((*treeNode)(nil)), Tag: `json:"root,omitempty"`},
+			{Name: "Home", Type: reflect.TypeOf(address{}), Tag: `json:"home"`},
+			{Name: fmt.Sprintf("Tool%d", i), Type: reflect.TypeOf(time.Duration(0)), Tag: `json:"timeout"`},
+		}))
+	}
+	return ret
+}
+
+func benchmarkLoad(b *testing.B, options ...StructToPropertiesOption) {
+	types := toolInputTypes(300)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for _, t := range types {
+				var s ToolInputSchema
+				if err := s.Load(reflect.New(t).Interface(), options...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkToolInputSchema_Load_Uncached registers 300 tools per op; a hook disables caching.
+// There is no benchmark of the generator before per-call state: it shared the set of visited types
+// between goroutines, so concurrent loads were not slower but could misdetect cycles. Compared with
+// BenchmarkToolInputSchema_Load_Cached this measures what memoization saves.
+func BenchmarkToolInputSchema_Load_Uncached(b *testing.B) {
+	benchmarkLoad(b, WithSkipFieldHook(func(reflect.StructField) bool { return false }))
+}
+
+// BenchmarkToolInputSchema_Load_Cached registers 300 tools per op with memoized schemas.
+func BenchmarkToolInputSchema_Load_Cached(b *testing.B) {
+	benchmarkLoad(b)
+}
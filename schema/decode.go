@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Unmarshal decodes JSON data into v like json.Unmarshal, resolving registered interface types
+// to their concrete implementations and applying the Decode function of registered type overrides.
+// It shares the decoder of DecodeArguments, including its options and *ArgumentError paths.
+func Unmarshal(data []byte, v any, opts ...DecodeOption) error {
+	options := &decodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer, got %T", v)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return options.decode(value, rv.Elem())
+}
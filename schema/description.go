@@ -0,0 +1,229 @@
+package schema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Descriptions maps "Type" and "Type.Field" names of a package to their doc comments.
+type Descriptions map[string]string
+
+// packageDescriptions holds registered descriptions keyed by package import path.
+var packageDescriptions = syncmap.NewMap[string, Descriptions]()
+
+// RegisterDescriptions registers doc comment descriptions for the package with the given import path,
+// typically from a file produced by WriteDescriptions in a go:generate step.
+func RegisterDescriptions(pkgPath string, descriptions Descriptions) {
+	merged := Descriptions{}
+	if existing, ok := packageDescriptions.Get(pkgPath); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range descriptions {
+		merged[k] = v
+	}
+	packageDescriptions.Put(pkgPath, merged)
+	invalidateCache()
+}
+
+// UnregisterDescriptions removes the descriptions registered for the package with the given import path.
+func UnregisterDescriptions(pkgPath string) {
+	packageDescriptions.Delete(pkgPath)
+	sourceDescriptions.Delete(pkgPath)
+	invalidateCache()
+}
+
+// TypeDescription returns the registered doc comment of named type t.
+func TypeDescription(t reflect.Type) string {
+	return lookupDescription(t, "")
+}
+
+// FieldDescription returns the registered doc comment of the named field of struct type t.
+func FieldDescription(t reflect.Type, field string) string {
+	return lookupDescription(t, field)
+}
+
+func lookupDescription(t reflect.Type, field string) string {
+	if t == nil {
+		return ""
+	}
+	t = structType(t)
+	if t.Name() == "" || t.PkgPath() == "" {
+		return ""
+	}
+	descriptions, ok := packageDescriptions.Get(t.PkgPath())
+	if !ok {
+		return ""
+	}
+	// generic instantiations share the doc comments of their type declaration
+	key, _, _ := strings.Cut(t.Name(), "[")
+	if field != "" {
+		key += "." + field
+	}
+	return descriptions[key]
+}
+
+// ParseDescriptions parses the Go sources (excluding tests) in dir and returns type and struct field doc comments.
+// A field without a doc comment uses its trailing line comment.
+func ParseDescriptions(dir string) (Descriptions, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	fileSet := token.NewFileSet()
+	ret := Descriptions{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fileSet, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if text := commentText(doc); text != "" {
+					ret[typeSpec.Name.Name] = text
+				}
+				structSpec, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range structSpec.Fields.List {
+					text := commentText(field.Doc)
+					if text == "" {
+						text = commentText(field.Comment)
+					}
+					if text == "" {
+						continue
+					}
+					for _, name := range field.Names {
+						ret[typeSpec.Name.Name+"."+name.Name] = text
+					}
+				}
+			}
+		}
+	}
+	return ret, nil
+}
+
+// LoadDescriptions locates the source of the package with the given import path, parses it and registers its descriptions.
+func LoadDescriptions(pkgPath string) error {
+	pkg, err := build.Import(pkgPath, ".", build.FindOnly)
+	if err != nil {
+		return fmt.Errorf("failed to locate package %s: %w", pkgPath, err)
+	}
+	descriptions, err := ParseDescriptions(pkg.Dir)
+	if err != nil {
+		return err
+	}
+	RegisterDescriptions(pkgPath, descriptions)
+	return nil
+}
+
+// WriteDescriptions writes a Go source file for package pkgName that registers descriptions for pkgPath at init time.
+func WriteDescriptions(w io.Writer, pkgName, pkgPath string, descriptions Descriptions) error {
+	keys := make([]string, 0, len(descriptions))
+	for k := range descriptions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var builder strings.Builder
+	builder.WriteString("// Code generated by schema.WriteDescriptions. DO NOT EDIT.\n\n")
+	builder.WriteString("package " + pkgName + "\n\n")
+	builder.WriteString("import \"github.com/viant/mcp-protocol/schema\"\n\n")
+	builder.WriteString("func init() {\n")
+	builder.WriteString("\tschema.RegisterDescriptions(" + strconv.Quote(pkgPath) + ", schema.Descriptions{\n")
+	for _, k := range keys {
+		builder.WriteString("\t\t" + strconv.Quote(k) + ": " + strconv.Quote(descriptions[k]) + ",\n")
+	}
+	builder.WriteString("\t})\n}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// commentText returns the comment text with lines joined by spaces and paragraphs kept apart.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	var paragraphs []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(group.Text()), "\n\n") {
+		if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// sourceDescriptions tracks packages whose sources were already loaded by WithSourceDescriptions.
+var sourceDescriptions = syncmap.NewMap[string, error]()
+
+// ensureDescriptions loads the descriptions of the package declaring t from source, once per package,
+// and returns the error of that load.
+func ensureDescriptions(t reflect.Type) error {
+	t = structType(t)
+	pkgPath := t.PkgPath()
+	if pkgPath == "" {
+		return nil
+	}
+	if err, ok := sourceDescriptions.Get(pkgPath); ok {
+		return err
+	}
+	err := LoadDescriptions(pkgPath)
+	sourceDescriptions.Put(pkgPath, err)
+	return err
+}
+
+// WithSourceDescriptions parses the sources of each package declaring a generated struct type on first use,
+// so that properties without a description tag take the field's doc comment, or else its type's.
+// Descriptions registered with RegisterDescriptions are used even without this option.
+// Sources that cannot be located or parsed are reported by ToolInputSchema.LoadType; binaries deployed
+// without sources should register descriptions generated by cmd/schemadoc instead.
+func WithSourceDescriptions() StructToPropertiesOption {
+	return func(o *structToPropertiesOptions) {
+		o.SourceDescriptions = true
+	}
+}
+
+// fieldDescription returns the doc comment description of field of struct type owner.
+func (g *generator) fieldDescription(owner reflect.Type, field reflect.StructField) string {
+	if g.options.SourceDescriptions {
+		// report each failed package once per generation
+		if err := ensureDescriptions(owner); err != nil && !slices.Contains(g.errs, err) {
+			g.errs = append(g.errs, err)
+		}
+	}
+	if desc := FieldDescription(owner, field.Name); desc != "" {
+		return desc
+	}
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+		fieldType = fieldType.Elem()
+	}
+	return TypeDescription(fieldType)
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"errors"
+	"go/parser"
+	gotoken "go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test: doc comments are parsed from source and fill missing property descriptions.
+func TestStructToProperties_SourceDescriptions(t *testing.T) {
+	dir := t.TempDir()
+	source := `package sample
+
+// Query selects records.
+type Query struct {
+	// Limit caps the number of
+	// returned records.
+	Limit int
+	Name  string // Name filters by name.
+	Plain bool
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "sample.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	descriptions, err := ParseDescriptions(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := Descriptions{"Query": "Query selects records.", "Query.Limit": "Limit caps the number of returned records.", "Query.Name": "Name filters by name."}
+	if !reflect.DeepEqual(descriptions, expect) {
+		t.Fatalf("unexpected descriptions: %#v", descriptions)
+	}
+	var generated strings.Builder
+	if err = WriteDescriptions(&generated, "sample", "example.com/sample", descriptions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = parser.ParseFile(gotoken.NewFileSet(), "gen.go", generated.String(), 0); err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, generated.String())
+	}
+
+	props, _ := StructToProperties(reflect.TypeOf(Implementation{}), WithSourceDescriptions())
+	if desc, _ := prop(props, "description")["description"].(string); !strings.HasPrefix(desc, "An optional human-readable description") {
+		t.Fatalf("unexpected description: %#v", prop(props, "description"))
+	}
+	if prop(props, "name")["description"] == nil {
+		t.Fatalf("expected name description, got: %#v", prop(props, "name"))
+	}
+
+	type documented struct {
+		Tagged   string `json:"tagged" description:"from tag"`
+		Untagged string `json:"untagged"`
+		Money    money  `json:"money"`
+	}
+	t.Cleanup(func() { UnregisterDescriptions("github.com/viant/mcp-protocol/schema") })
+	RegisterDescriptions("github.com/viant/mcp-protocol/schema", Descriptions{
+		"documented.Tagged": "ignored", "documented.Untagged": "from doc", "money": "an amount with currency",
+	})
+	props, _ = StructToProperties(reflect.TypeOf(documented{}))
+	if prop(props, "tagged")["description"] != "from tag" || prop(props, "untagged")["description"] != "from doc" {
+		t.Fatalf("unexpected descriptions: %#v", props)
+	}
+	if prop(props, "money")["description"] != "an amount with currency" {
+		t.Fatalf("expected type description fallback, got: %#v", prop(props, "money"))
+	}
+}
+
+// Test: sources that cannot be loaded are reported once by LoadType rather than silently skipped.
+func TestToolInputSchema_LoadSourceDescriptionsError(t *testing.T) {
+	type undocumented struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	loadErr := errors.New("failed to locate package github.com/viant/mcp-protocol/schema")
+	t.Cleanup(func() { UnregisterDescriptions("github.com/viant/mcp-protocol/schema") })
+	sourceDescriptions.Put("github.com/viant/mcp-protocol/schema", loadErr)
+
+	var inputSchema ToolInputSchema
+	err := inputSchema.Load(undocumented{}, WithSourceDescriptions())
+	if !errors.Is(err, loadErr) || err.Error() != loadErr.Error() {
+		t.Fatalf("expected %v, got: %v", loadErr, err)
+	}
+	if err = inputSchema.Load(undocumented{}); err != nil {
+		t.Fatalf("unexpected error without source descriptions: %v", err)
+	}
+}
@@ -1,3 +1,7 @@
 package schema
 
-//go:generate go run github.com/atombender/go-jsonschema@latest ./schema-2024-11-05.json -p schema -o ./types.go
+// Types are generated from the vendored spec schema merged with schema-overlay.json, which declares
+// the $defs and additionalProperties keywords of tool input and output schemas.
+//go:generate go run ./internal/specoverlay -o ./schema-generate.json ./schema-2025-11-25.json ./schema-overlay.json
+//go:generate go run github.com/atombender/go-jsonschema@latest ./schema-generate.json -p schema -o ./types.go
+//go:generate rm ./schema-generate.json
@@ -0,0 +1,66 @@
+// Command specoverlay merges local additions into a copy of the vendored MCP spec schema before
+// types are generated from it, so that the vendored schema stays identical to upstream.
+//
+//	go run ./internal/specoverlay -o merged.json schema.json overlay.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	output := flag.String("o", "", "output file")
+	flag.Parse()
+	if *output == "" || flag.NArg() != 2 {
+		log.Fatal("usage: specoverlay -o output schema overlay")
+	}
+	if err := run(flag.Arg(0), flag.Arg(1), *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(schemaFile, overlayFile, output string) error {
+	spec, err := load(schemaFile)
+	if err != nil {
+		return err
+	}
+	overlay, err := load(overlayFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(merge(spec, overlay), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
+
+func load(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string]interface{}
+	if err = json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return ret, nil
+}
+
+// merge adds overlay to dst: objects are merged recursively, other values are replaced.
+func merge(dst, overlay map[string]interface{}) map[string]interface{} {
+	for key, value := range overlay {
+		existing, ok1 := dst[key].(map[string]interface{})
+		additional, ok2 := value.(map[string]interface{})
+		if ok1 && ok2 {
+			dst[key] = merge(existing, additional)
+			continue
+		}
+		dst[key] = value
+	}
+	return dst
+}
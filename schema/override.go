@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// SchemaProvider is implemented by types that supply their own JSON schema.
+type SchemaProvider interface {
+	JSONSchema() map[string]interface{}
+}
+
+// TypeOverride replaces the generated schema of a type and, optionally, how arguments decode into it.
+type TypeOverride struct {
+	Schema map[string]interface{}
+	// Decode decodes data into v, an addressable value of the overridden type; nil uses encoding/json.
+	Decode func(data []byte, v reflect.Value) error
+}
+
+var typeOverrides = syncmap.NewMap[reflect.Type, *TypeOverride]()
+
+// RegisterType registers a schema override for type T, replacing any previous one including built-ins.
+func RegisterType[T any](override TypeOverride) {
+	typeOverrides.Put(reflect.TypeOf((*T)(nil)).Elem(), &override)
+	invalidateCache()
+}
+
+// UnregisterType removes the schema override registered for type T, including a built-in one.
+func UnregisterType[T any]() {
+	typeOverrides.Delete(reflect.TypeOf((*T)(nil)).Elem())
+	invalidateCache()
+}
+
+// LookupType returns the schema override registered for t.
+func LookupType(t reflect.Type) (*TypeOverride, bool) {
+	return typeOverrides.Get(t)
+}
+
+var (
+	schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func init() {
+	RegisterType[time.Time](TypeOverride{Schema: map[string]interface{}{"type": "string", "format": "date-time"}})
+	RegisterType[time.Duration](TypeOverride{
+		Schema: map[string]interface{}{"type": "string", "pattern": `^-?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$`},
+		Decode: decodeDuration,
+	})
+	RegisterType[[]byte](TypeOverride{Schema: map[string]interface{}{"type": "string", "contentEncoding": "base64"}})
+	RegisterType[json.RawMessage](TypeOverride{Schema: map[string]interface{}{}})
+	RegisterType[net.IP](TypeOverride{Schema: map[string]interface{}{
+		"type":  "string",
+		"anyOf": []interface{}{map[string]interface{}{"format": "ipv4"}, map[string]interface{}{"format": "ipv6"}},
+	}})
+	RegisterType[url.URL](TypeOverride{
+		Schema: map[string]interface{}{"type": "string", "format": "uri"},
+		Decode: decodeURL,
+	})
+	RegisterType[big.Int](TypeOverride{Schema: map[string]interface{}{"type": "integer"}})
+}
+
+// customSchema returns the schema supplied for t by a type override, including the built-in ones,
+// a SchemaProvider implementation or, failing those, an encoding.TextMarshaler implementation.
+// Pointer types are unwrapped by the caller, so that they keep the schema of their element type.
+func customSchema(t reflect.Type) (map[string]interface{}, bool) {
+	if override, ok := LookupType(t); ok {
+		return copySchema(override.Schema), true
+	}
+	if t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr {
+		return nil, false
+	}
+	if t.Implements(schemaProviderType) {
+		provider := reflect.New(t).Elem().Interface().(SchemaProvider)
+		return copySchema(provider.JSONSchema()), true
+	}
+	if reflect.PointerTo(t).Implements(schemaProviderType) {
+		provider := reflect.New(t).Interface().(SchemaProvider)
+		return copySchema(provider.JSONSchema()), true
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}, true
+	}
+	return nil, false
+}
+
+// copySchema returns a deep copy of schema so that callers can amend it.
+func copySchema(schema map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		ret[k] = copySchemaValue(v)
+	}
+	return ret
+}
+
+func copySchemaValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		return copySchema(actual)
+	case []interface{}:
+		ret := make([]interface{}, len(actual))
+		for i, item := range actual {
+			ret[i] = copySchemaValue(item)
+		}
+		return ret
+	case ToolInputSchemaProperties:
+		return copyProperties(actual)
+	case map[string]map[string]interface{}:
+		return map[string]map[string]interface{}(copyProperties(actual))
+	case []string:
+		return append([]string{}, actual...)
+	}
+	return v
+}
+
+// decodeDuration accepts a Go duration string or a number of nanoseconds.
+func decodeDuration(data []byte, v reflect.Value) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch actual := value.(type) {
+	case string:
+		duration, err := time.ParseDuration(actual)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(duration))
+	case float64:
+		v.SetInt(int64(actual))
+	default:
+		return fmt.Errorf("invalid duration: %s", data)
+	}
+	return nil
+}
+
+func decodeURL(data []byte, v reflect.Value) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(*u))
+	return nil
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type money struct {
+	Amount   int64
+	Currency string
+}
+
+func (money) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "string", "pattern": `^[0-9]+ [A-Z]{3}$`}
+}
+
+type apiToken [4]byte
+
+func (t apiToken) MarshalText() ([]byte, error) { return []byte("tok"), nil }
+
+type overrideInput struct {
+	Timeout  time.Duration   `json:"timeout"`
+	Data     []byte          `json:"data"`
+	Raw      json.RawMessage `json:"raw"`
+	Address  net.IP          `json:"address"`
+	Endpoint *url.URL        `json:"endpoint"`
+	Price    money           `json:"price" description:"price"`
+	Token    apiToken        `json:"token"`
+	When     time.Time       `json:"when"`
+}
+
+// Test: built-in overrides, SchemaProvider and TextMarshaler types replace the generated schema.
+func TestStructToProperties_TypeOverrides(t *testing.T) {
+	props, _ := StructToProperties(reflect.TypeOf(overrideInput{}))
+	expect := map[string]map[string]interface{}{
+		"timeout": {"type": "string"},
+		"data":    {"type": "string", "contentEncoding": "base64"},
+		"raw":     {},
+		"address": {"type": "string"},
+		"price":   {"type": "string", "description": "price"},
+		"token":   {"type": "string"},
+		"when":    {"type": "string", "format": "date-time"},
+	}
+	for name, expected := range expect {
+		actual := prop(props, name)
+		for k, v := range expected {
+			if actual[k] != v {
+				t.Fatalf("expected %s[%s]=%v, got: %#v", name, k, v, actual)
+			}
+		}
+		if len(expected) == 0 && len(actual) != 0 {
+			t.Fatalf("expected empty schema for %s, got: %#v", name, actual)
+		}
+	}
+	if endpoint := prop(props, "endpoint"); endpoint["format"] != "uri" || endpoint["nullable"] != true {
+		t.Fatalf("unexpected endpoint schema: %#v", endpoint)
+	}
+
+	type celsius float64
+	RegisterType[celsius](TypeOverride{Schema: map[string]interface{}{"type": "number", "minimum": -273.15}})
+	t.Cleanup(UnregisterType[celsius])
+	props, _ = StructToProperties(reflect.TypeOf(struct {
+		Temp celsius `json:"temp"`
+	}{}))
+	if prop(props, "temp")["minimum"] != -273.15 {
+		t.Fatalf("unexpected temp schema: %#v", prop(props, "temp"))
+	}
+}
+
+type percent float64
+
+func (*percent) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "number", "minimum": 0, "maximum": 100}
+}
+
+// Test: pointers to overridden, SchemaProvider and TextMarshaler types keep the element schema and are nullable.
+func TestStructToProperties_PointerOverrides(t *testing.T) {
+	props, _ := StructToProperties(reflect.TypeOf(struct {
+		Since    *time.Time `json:"since"`
+		Discount *money     `json:"discount"`
+		Ratio    *percent   `json:"ratio"`
+		Token    *apiToken  `json:"token"`
+	}{}))
+	expect := map[string]map[string]interface{}{
+		"since":    {"type": "string", "format": "date-time", "nullable": true},
+		"discount": {"type": "string", "pattern": `^[0-9]+ [A-Z]{3}$`, "nullable": true},
+		"ratio":    {"type": "number", "minimum": 0, "maximum": 100, "nullable": true},
+		"token":    {"type": "string", "nullable": true},
+	}
+	for name, expected := range expect {
+		if actual := prop(props, name); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("expected %s schema %#v, got: %#v", name, expected, actual)
+		}
+	}
+}
+
+// Test: Unmarshal applies override decoders, e.g. duration strings and URLs.
+func TestUnmarshal_TypeOverrides(t *testing.T) {
+	var input overrideInput
+	data := `{"timeout":"1m30s","data":"aGk=","raw":{"a":1},"address":"10.0.0.1","endpoint":"https://example.com/x"}`
+	if err := Unmarshal([]byte(data), &input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Timeout != 90*time.Second || string(input.Data) != "hi" || string(input.Raw) != `{"a":1}` {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+	if input.Address.String() != "10.0.0.1" || input.Endpoint == nil || input.Endpoint.Host != "example.com" {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+	if err := Unmarshal([]byte(`{"timeout":1000}`), &input); err != nil || input.Timeout != time.Microsecond {
+		t.Fatalf("expected nanosecond timeout, got: %v %v", input.Timeout, err)
+	}
+	if err := Unmarshal([]byte(`{"timeout":"soon"}`), &input); err == nil {
+		t.Fatalf("expected invalid duration error")
+	}
+}
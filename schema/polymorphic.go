@@ -0,0 +1,141 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/viant/mcp-protocol/syncmap"
+)
+
+// Variant is a concrete type registered for an interface, identified by its discriminator value.
+type Variant struct {
+	Value string
+	Type  reflect.Type
+}
+
+// VariantOf returns a Variant of type T identified by the discriminator value.
+// T may be a struct or a pointer to struct, whichever implements the interface.
+func VariantOf[T any](value string) Variant {
+	return Variant{Value: value, Type: reflect.TypeOf((*T)(nil)).Elem()}
+}
+
+// Polymorphic describes the concrete implementations of an interface type.
+// With a Discriminator the schema is a oneOf keyed by that property; without it an anyOf
+// whose variants are tried in registration order when decoding.
+type Polymorphic struct {
+	Interface     reflect.Type
+	Discriminator string
+	Variants      []Variant
+}
+
+var polymorphicTypes = syncmap.NewMap[reflect.Type, *Polymorphic]()
+
+// RegisterInterface declares the concrete implementations of interface type I, optionally with the
+// name of the discriminator property that selects them. Registering I again replaces the previous entry.
+func RegisterInterface[I any](discriminator string, variants ...Variant) error {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("expected an interface type, got %s", iface)
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("no implementations for %s", iface)
+	}
+	values := map[string]bool{}
+	for _, impl := range variants {
+		if !impl.Type.Implements(iface) {
+			return fmt.Errorf("%s does not implement %s", impl.Type, iface)
+		}
+		if structType(impl.Type).Kind() != reflect.Struct {
+			return fmt.Errorf("implementation %s of %s is not a struct", impl.Type, iface)
+		}
+		if discriminator == "" {
+			continue
+		}
+		if impl.Value == "" {
+			return fmt.Errorf("missing %s value for %s", discriminator, impl.Type)
+		}
+		if values[impl.Value] {
+			return fmt.Errorf("duplicate %s value %q for %s", discriminator, impl.Value, iface)
+		}
+		values[impl.Value] = true
+	}
+	polymorphicTypes.Put(iface, &Polymorphic{Interface: iface, Discriminator: discriminator, Variants: variants})
+	invalidateCache()
+	return nil
+}
+
+// UnregisterInterface removes the implementations registered for interface type I.
+func UnregisterInterface[I any]() {
+	polymorphicTypes.Delete(reflect.TypeOf((*I)(nil)).Elem())
+	invalidateCache()
+}
+
+// LookupInterface returns the registered implementations of interface type t.
+func LookupInterface(t reflect.Type) (*Polymorphic, bool) {
+	if t == nil || t.Kind() != reflect.Interface {
+		return nil, false
+	}
+	return polymorphicTypes.Get(t)
+}
+
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// polymorphicSchema returns the oneOf/anyOf schema of a registered interface; the OpenAPI
+// discriminator keyword is emitted only under ProfileOpenAPI.
+func (g *generator) polymorphicSchema(p *Polymorphic) map[string]interface{} {
+	variants := make([]interface{}, 0, len(p.Variants))
+	for _, impl := range p.Variants {
+		variant := g.buildJSONSchema(structType(impl.Type), false)
+		if p.Discriminator != "" {
+			variant = withDiscriminator(variant, p.Discriminator, impl.Value)
+		}
+		variants = append(variants, variant)
+	}
+	if p.Discriminator == "" {
+		return map[string]interface{}{"anyOf": variants}
+	}
+	ret := map[string]interface{}{"oneOf": variants}
+	// discriminator is an OpenAPI keyword; other profiles rely on the const constraint of each variant
+	if g.options.Profile == ProfileOpenAPI {
+		ret["discriminator"] = map[string]interface{}{"propertyName": p.Discriminator}
+	}
+	return ret
+}
+
+// withDiscriminator constrains the discriminator property of a variant schema to value.
+func withDiscriminator(variant map[string]interface{}, discriminator, value string) map[string]interface{} {
+	constraint := map[string]interface{}{"type": "string", "const": value}
+	if _, ok := variant["$ref"]; ok {
+		return map[string]interface{}{"allOf": []interface{}{variant, map[string]interface{}{
+			"type":       "object",
+			"properties": ToolInputSchemaProperties{discriminator: constraint},
+			"required":   []string{discriminator},
+		}}}
+	}
+	properties, _ := variant["properties"].(ToolInputSchemaProperties)
+	if properties == nil {
+		properties = ToolInputSchemaProperties{}
+		variant["properties"] = properties
+	}
+	if existing, ok := properties[discriminator]; ok {
+		for k, v := range existing {
+			if _, ok := constraint[k]; !ok {
+				constraint[k] = v
+			}
+		}
+	}
+	properties[discriminator] = constraint
+	required, _ := variant["required"].([]string)
+	for _, name := range required {
+		if name == discriminator {
+			return variant
+		}
+	}
+	variant["required"] = append(required, discriminator)
+	return variant
+}
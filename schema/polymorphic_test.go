@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type shape interface{ area() float64 }
+
+type circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius"`
+}
+
+func (c circle) area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type rect struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r *rect) area() float64 { return r.Width * r.Height }
+
+type drawing interface{ draw() }
+
+type line struct {
+	Length int `json:"length"`
+}
+
+func (line) draw() {}
+
+type label struct {
+	Text string `json:"text"`
+}
+
+func (label) draw() {}
+
+type canvasInput struct {
+	Shape   shape            `json:"shape"`
+	Shapes  []shape          `json:"shapes,omitempty"`
+	ByName  map[string]shape `json:"byName,omitempty"`
+	Drawing drawing          `json:"drawing,omitempty"`
+	Title   string           `json:"title"`
+}
+
+// registerShapes registers the shape and drawing interfaces for the duration of the test.
+func registerShapes(t *testing.T) {
+	t.Cleanup(func() {
+		UnregisterInterface[shape]()
+		UnregisterInterface[drawing]()
+	})
+	if err := RegisterInterface[shape]("kind", VariantOf[circle]("circle"), VariantOf[*rect]("rect")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterInterface[drawing]("", VariantOf[line](""), VariantOf[label]("")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Test: registered interfaces produce oneOf with a discriminator, or anyOf without one.
+func TestStructToProperties_Polymorphic(t *testing.T) {
+	registerShapes(t)
+	if err := RegisterInterface[shape]("kind", VariantOf[rect]("rect")); err == nil {
+		t.Fatalf("expected error for a value type with a pointer receiver")
+	}
+	props, _ := StructToProperties(reflect.TypeOf(canvasInput{}))
+
+	shapeSchema := prop(props, "shape")
+	variants, _ := shapeSchema["oneOf"].([]interface{})
+	if len(variants) != 2 || !reflect.DeepEqual(shapeSchema["discriminator"], map[string]interface{}{"propertyName": "kind"}) {
+		t.Fatalf("unexpected shape schema: %#v", shapeSchema)
+	}
+	for i, value := range []string{"circle", "rect"} {
+		variant := variants[i].(map[string]interface{})
+		kind := variant["properties"].(ToolInputSchemaProperties)["kind"]
+		if kind["const"] != value {
+			t.Fatalf("expected kind const %v, got: %#v", value, variant)
+		}
+		if required, _ := variant["required"].([]string); !slices.Contains(required, "kind") {
+			t.Fatalf("expected kind to be required, got: %#v", variant)
+		}
+	}
+	if items, _ := prop(props, "shapes")["items"].(map[string]interface{}); items["oneOf"] == nil {
+		t.Fatalf("expected oneOf items, got: %#v", prop(props, "shapes"))
+	}
+	if anyOf, _ := prop(props, "drawing")["anyOf"].([]interface{}); len(anyOf) != 2 {
+		t.Fatalf("unexpected drawing schema: %#v", prop(props, "drawing"))
+	}
+	for _, profile := range []Profile{ProfileDraft202012, ProfileStrict} {
+		props, _ = StructToProperties(reflect.TypeOf(canvasInput{}), WithProfile(profile))
+		if shapeSchema := prop(props, "shape"); shapeSchema["oneOf"] == nil || shapeSchema["discriminator"] != nil {
+			t.Fatalf("expected oneOf without discriminator for profile %v, got: %#v", profile, shapeSchema)
+		}
+	}
+}
+
+// Test: Unmarshal decodes registered interfaces into their concrete implementations.
+func TestUnmarshal_Polymorphic(t *testing.T) {
+	registerShapes(t)
+	data := `{"title":"t","shape":{"kind":"circle","radius":2},"shapes":[{"kind":"rect","width":2,"height":3}],` +
+		`"byName":{"c":{"kind":"circle","radius":1}},"drawing":{"text":"hi"}}`
+	var input canvasInput
+	if err := Unmarshal([]byte(data), &input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c, ok := input.Shape.(circle); !ok || c.Radius != 2 || input.Title != "t" {
+		t.Fatalf("unexpected shape: %#v", input)
+	}
+	if r, ok := input.Shapes[0].(*rect); !ok || r.area() != 6 {
+		t.Fatalf("unexpected shapes: %#v", input.Shapes)
+	}
+	if _, ok := input.ByName["c"].(circle); !ok {
+		t.Fatalf("unexpected byName: %#v", input.ByName)
+	}
+	if l, ok := input.Drawing.(label); !ok || l.Text != "hi" {
+		t.Fatalf("unexpected drawing: %#v", input.Drawing)
+	}
+
+	if err := Unmarshal([]byte(`{"shape":{"kind":"square"}}`), &input); err == nil {
+		t.Fatalf("expected error for an unknown discriminator")
+	}
+	if err := Unmarshal([]byte(`{"shape":{"radius":1}}`), &input); err == nil {
+		t.Fatalf("expected error for a missing discriminator")
+	}
+}
@@ -0,0 +1,155 @@
+package schema
+
+import "sort"
+
+// Profile selects the JSON schema dialect emitted by schema generation.
+type Profile int
+
+const (
+	// ProfileOpenAPI marks optional values with the OpenAPI "nullable": true keyword (default).
+	ProfileOpenAPI Profile = iota
+	// ProfileDraft202012 emits standard JSON Schema 2020-12: nullable values become type arrays
+	// with "null" (or an anyOf union with {"type":"null"}) and the root declares $schema.
+	ProfileDraft202012
+	// ProfileStrict extends ProfileDraft202012 for strict LLM tool modes: every object with declared
+	// properties sets additionalProperties:false and requires all properties, optional ones as nullable unions.
+	ProfileStrict
+)
+
+// Draft202012 is the $schema URI of JSON Schema 2020-12.
+const Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// annotationKeywords stay on the outer schema when a nullable schema is wrapped in an anyOf union.
+var annotationKeywords = []string{"title", "description", "default", "examples", "deprecated"}
+
+// WithProfile selects the output profile, applied to properties, $defs and, in Load, the root schema.
+func WithProfile(profile Profile) StructToPropertiesOption {
+	return func(o *structToPropertiesOptions) {
+		o.Profile = profile
+	}
+}
+
+// applyProfile rewrites generated properties, required fields and definitions for the profile.
+func (p Profile) applyProfile(properties ToolInputSchemaProperties, required []string, defs map[string]map[string]interface{}) []string {
+	if p == ProfileOpenAPI {
+		return required
+	}
+	required = p.applyProperties(properties, required)
+	for name, def := range defs {
+		defs[name] = p.apply(def)
+	}
+	return required
+}
+
+// profileOf returns the profile selected by options.
+func profileOf(options []StructToPropertiesOption) Profile {
+	var opts structToPropertiesOptions
+	for _, o := range options {
+		o(&opts)
+	}
+	return opts.Profile
+}
+
+// root returns the $schema and additionalProperties values of a root schema.
+func (p Profile) root() (*string, interface{}) {
+	switch p {
+	case ProfileDraft202012:
+		schema := Draft202012
+		return &schema, nil
+	case ProfileStrict:
+		schema := Draft202012
+		return &schema, false
+	}
+	return nil, nil
+}
+
+// applyProperties rewrites property schemas in place and returns the required fields.
+func (p Profile) applyProperties(properties map[string]map[string]interface{}, required []string) []string {
+	if p == ProfileStrict {
+		declared := map[string]bool{}
+		for _, name := range required {
+			declared[name] = true
+		}
+		var optional []string
+		for name := range properties {
+			if !declared[name] {
+				optional = append(optional, name)
+			}
+		}
+		sort.Strings(optional)
+		for _, name := range optional {
+			properties[name]["nullable"] = true
+		}
+		required = append(required, optional...)
+	}
+	for name, property := range properties {
+		properties[name] = p.apply(property)
+	}
+	return required
+}
+
+// apply returns the schema rewritten for the profile, recursing into nested schemas.
+func (p Profile) apply(schema map[string]interface{}) map[string]interface{} {
+	switch properties := schema["properties"].(type) {
+	case ToolInputSchemaProperties:
+		p.applyObject(schema, properties)
+	case map[string]map[string]interface{}:
+		p.applyObject(schema, properties)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		schema["items"] = p.apply(items)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		schema["additionalProperties"] = p.apply(additional)
+	}
+	for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+		if variants, ok := schema[keyword].([]interface{}); ok {
+			for i, variant := range variants {
+				if variantSchema, ok := variant.(map[string]interface{}); ok {
+					variants[i] = p.apply(variantSchema)
+				}
+			}
+		}
+	}
+	nullable, _ := schema["nullable"].(bool)
+	delete(schema, "nullable")
+	if !nullable || len(schema) == 0 {
+		return schema
+	}
+	if enum, ok := schema["enum"].([]string); ok {
+		values := make([]interface{}, 0, len(enum)+1)
+		for _, value := range enum {
+			values = append(values, value)
+		}
+		schema["enum"] = append(values, nil)
+	} else if enum, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = append(enum, nil)
+	}
+	switch typ := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{typ, "null"}
+		return schema
+	case []interface{}:
+		schema["type"] = append(typ, "null")
+		return schema
+	}
+	union := map[string]interface{}{}
+	for _, keyword := range annotationKeywords {
+		if value, ok := schema[keyword]; ok {
+			union[keyword] = value
+			delete(schema, keyword)
+		}
+	}
+	union["anyOf"] = []interface{}{schema, map[string]interface{}{"type": "null"}}
+	return union
+}
+
+func (p Profile) applyObject(schema map[string]interface{}, properties map[string]map[string]interface{}) {
+	required, _ := schema["required"].([]string)
+	if required = p.applyProperties(properties, required); len(required) > 0 {
+		schema["required"] = required
+	}
+	if p == ProfileStrict {
+		schema["additionalProperties"] = false
+	}
+}
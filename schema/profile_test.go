@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type profileItem struct {
+	Label *string `json:"label"`
+}
+
+type profileInput struct {
+	Name   string            `json:"name"`
+	Limit  *int              `json:"limit,omitempty"`
+	Mode   *string           `json:"mode,omitempty" choice:"fast" choice:"slow"`
+	Items  []profileItem     `json:"items,omitempty"`
+	Root   *treeNode         `json:"root,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Test: 2020-12 and strict profiles replace nullable with null unions; strict closes and requires all properties.
+func TestToolInputSchema_LoadProfiles(t *testing.T) {
+	s := loadSchema(t, profileInput{})
+	if s.Schema != nil || prop(s.Properties, "limit")["nullable"] != true {
+		t.Fatalf("expected OpenAPI profile by default, got: %#v", s)
+	}
+
+	s = loadSchema(t, profileInput{}, WithProfile(ProfileDraft202012))
+	if s.Schema == nil || *s.Schema != Draft202012 || s.AdditionalProperties != nil {
+		t.Fatalf("unexpected root: %#v", s)
+	}
+	if limit := prop(s.Properties, "limit"); !reflect.DeepEqual(limit["type"], []interface{}{"integer", "null"}) || limit["nullable"] != nil {
+		t.Fatalf("unexpected limit schema: %#v", limit)
+	}
+	if mode := prop(s.Properties, "mode"); !reflect.DeepEqual(mode["enum"], []interface{}{"fast", "slow", nil}) {
+		t.Fatalf("unexpected mode schema: %#v", mode)
+	}
+	root := prop(s.Properties, "root")
+	if anyOf, _ := root["anyOf"].([]interface{}); len(anyOf) != 2 || anyOf[0].(map[string]interface{})["$ref"] != "#/$defs/treeNode" {
+		t.Fatalf("unexpected root schema: %#v", root)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"name"}) {
+		t.Fatalf("unexpected required: %v", s.Required)
+	}
+
+	s = loadSchema(t, profileInput{}, WithProfile(ProfileStrict))
+	if s.AdditionalProperties != false {
+		t.Fatalf("expected additionalProperties false, got: %#v", s.AdditionalProperties)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"name", "items", "labels", "limit", "mode", "root"}) {
+		t.Fatalf("unexpected required: %v", s.Required)
+	}
+	items := prop(s.Properties, "items")
+	if !reflect.DeepEqual(items["type"], []interface{}{"array", "null"}) {
+		t.Fatalf("unexpected items schema: %#v", items)
+	}
+	item := items["items"].(map[string]interface{})
+	if item["additionalProperties"] != false || !reflect.DeepEqual(item["required"], []string{"label"}) {
+		t.Fatalf("unexpected item schema: %#v", item)
+	}
+	node := s.Defs["treeNode"]
+	if node["additionalProperties"] != false || node["required"] == nil {
+		t.Fatalf("unexpected treeNode definition: %#v", node)
+	}
+	data, err := json.Marshal(s)
+	if err != nil || strings.Contains(string(data), "nullable") {
+		t.Fatalf("unexpected strict schema: %s %v", data, err)
+	}
+}
@@ -0,0 +1,213 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericKeywords are validation keywords taking a number, applied to numeric values.
+var numericKeywords = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}
+
+// stringKeywords are validation keywords applied to string values.
+var stringKeywords = []string{"minLength", "maxLength", "pattern"}
+
+// arrayKeywords are validation keywords applied to arrays.
+var arrayKeywords = []string{"minItems", "maxItems", "uniqueItems"}
+
+// tagExpr matches every key:"value" pair of a struct tag.
+var tagExpr = regexp.MustCompile(`(?:^|\s)([^\s:"]+):"((?:[^"\\]|\\.)*)"`)
+
+// tagValues returns all values of a possibly repeated struct tag key, e.g. choice:"a" choice:"b".
+func tagValues(tag reflect.StructTag, key string) []string {
+	var ret []string
+	for _, match := range tagExpr.FindAllStringSubmatch(string(tag), -1) {
+		if match[1] != key {
+			continue
+		}
+		value, err := strconv.Unquote(`"` + match[2] + `"`)
+		if err != nil {
+			value = match[2]
+		}
+		ret = append(ret, value)
+	}
+	return ret
+}
+
+// applyValidationTags sets JSON schema validation and annotation keywords from struct tags.
+// Numeric and string keywords on an array field constrain its items; values are converted to the
+// field's type so that, e.g., minimum:"1" on an int field becomes the integer 1. Values that do not
+// parse are reported rather than dropped.
+func applyValidationTags(fieldSchema map[string]interface{}, field reflect.StructField) error {
+	valueType := field.Type
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	itemSchema := fieldSchema
+	itemType := valueType
+	if valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array {
+		if items, ok := fieldSchema["items"].(map[string]interface{}); ok {
+			itemSchema = items
+			itemType = valueType.Elem()
+			for itemType.Kind() == reflect.Ptr {
+				itemType = itemType.Elem()
+			}
+		}
+	}
+
+	var errs []error
+	for _, key := range numericKeywords {
+		if value, ok := field.Tag.Lookup(key); ok {
+			number, ok := parseNumber(value, isInteger(itemType) && key != "multipleOf")
+			if !ok {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a number", key, value))
+				continue
+			}
+			itemSchema[key] = number
+		}
+	}
+	for _, key := range stringKeywords {
+		value, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		if key == "pattern" {
+			if _, err := regexp.Compile(value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid pattern %q: %w", value, err))
+				continue
+			}
+			itemSchema[key] = value
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: expected a non-negative integer", key, value))
+			continue
+		}
+		itemSchema[key] = n
+	}
+	for _, key := range arrayKeywords {
+		value, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		if key == "uniqueItems" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a boolean", key, value))
+				continue
+			}
+			fieldSchema[key] = b
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: expected a non-negative integer", key, value))
+			continue
+		}
+		fieldSchema[key] = n
+	}
+
+	if value, ok := field.Tag.Lookup("default"); ok && value != "" {
+		if typed, err := typedValue(value, valueType, fieldSchema); err != nil {
+			errs = append(errs, fmt.Errorf("invalid default %q: %w", value, err))
+		} else {
+			fieldSchema["default"] = typed
+		}
+	}
+	if value, ok := field.Tag.Lookup("const"); ok {
+		if typed, err := typedValue(value, valueType, fieldSchema); err != nil {
+			errs = append(errs, fmt.Errorf("invalid const %q: %w", value, err))
+		} else {
+			fieldSchema["const"] = typed
+		}
+	}
+	if examples := tagValues(field.Tag, "example"); len(examples) > 0 {
+		values := make([]interface{}, 0, len(examples))
+		for _, example := range examples {
+			typed, err := typedValue(example, valueType, fieldSchema)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid example %q: %w", example, err))
+				continue
+			}
+			values = append(values, typed)
+		}
+		fieldSchema["examples"] = values
+	}
+	if title := field.Tag.Get("title"); title != "" {
+		fieldSchema["title"] = title
+	}
+	if value, ok := field.Tag.Lookup("deprecated"); ok {
+		deprecated, err := strconv.ParseBool(value)
+		switch {
+		case value == "":
+			fieldSchema["deprecated"] = true
+		case err != nil:
+			errs = append(errs, fmt.Errorf("invalid deprecated %q: expected a boolean", value))
+		case deprecated:
+			fieldSchema["deprecated"] = true
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isInteger(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// parseNumber parses a numeric tag value, as an int64 when integer is set and the value is integral.
+func parseNumber(value string, integer bool) (interface{}, bool) {
+	if integer {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n, true
+		}
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// typedValue converts a tag value to the JSON value of type t: verbatim when the field schema is a string
+// (including types with a custom string schema such as time.Time), otherwise parsed by kind, with
+// composite values decoded as JSON.
+func typedValue(value string, t reflect.Type, fieldSchema map[string]interface{}) (interface{}, error) {
+	if t.Kind() == reflect.String || fieldSchema["type"] == "string" {
+		return value, nil
+	}
+	switch {
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("expected a boolean")
+		}
+		return b, nil
+	case isInteger(t):
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("expected an integer")
+		}
+		return n, nil
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return f, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &decoded); err != nil {
+		return nil, fmt.Errorf("expected JSON: %w", err)
+	}
+	return decoded, nil
+}
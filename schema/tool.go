@@ -2,68 +2,199 @@ package schema
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 )
 
-// visitedTypes tracks types currently being processed to detect cyclic definitions.
-var (
-	visitedMu    sync.Mutex
-	visitedTypes map[reflect.Type]bool
+// DefinitionsMode controls which struct types are emitted once under $defs and referenced with $ref.
+type DefinitionsMode int
+
+const (
+	// DefinitionsInline inlines every type; a recursive reference collapses to {"type":"object"}.
+	DefinitionsInline DefinitionsMode = iota
+	// DefinitionsRecursive moves recursive named struct types to $defs.
+	DefinitionsRecursive
+	// DefinitionsShared moves recursive named struct types and types used more than once to $defs.
+	DefinitionsShared
 )
 
-// buildJSONSchema constructs a JSON-schema fragment that represents the supplied Go reflect.Type.
-// The `inSlice` flag is an internal recursion marker: it is true when the type
-// currently being processed is the *element* of a slice/array. That allows the
-// algorithm to decide whether automatically added `nullable:true` (for pointer
-// types) should be kept or suppressed.
-// It detects and breaks cycles in types, returning an empty object schema on recursion.
-func buildJSONSchema(t reflect.Type, inSlice bool, opts ...StructToPropertiesOption) map[string]interface{} {
-	schema := make(map[string]interface{})
+// defsPrefix is the JSON pointer prefix of schema definitions.
+const defsPrefix = "#/$defs/"
 
-	// initialize cycle-detection state on outermost call
-	visitedMu.Lock()
-	first := visitedTypes == nil
-	if first {
-		visitedTypes = make(map[reflect.Type]bool)
-	}
-	// detect cyclic types: if this type is already being processed, break the cycle
-	if visitedTypes[t] {
-		visitedMu.Unlock()
-		if first {
-			visitedMu.Lock()
-			visitedTypes = nil
-			visitedMu.Unlock()
+// generator holds the state of a single schema generation.
+type generator struct {
+	options   structToPropertiesOptions
+	root      reflect.Type
+	defs      map[string]map[string]interface{}
+	refs      map[reflect.Type]string
+	names     map[string]reflect.Type
+	visiting  map[reflect.Type]bool
+	counts    map[reflect.Type]int
+	recursive map[reflect.Type]bool
+	errs      []error
+}
+
+func newGenerator(root reflect.Type, options structToPropertiesOptions) *generator {
+	ret := &generator{
+		options:   options,
+		root:      root,
+		refs:      map[reflect.Type]string{},
+		names:     map[string]reflect.Type{},
+		visiting:  map[reflect.Type]bool{},
+		counts:    map[reflect.Type]int{},
+		recursive: map[reflect.Type]bool{},
+	}
+	if ret.options.Definitions != DefinitionsInline {
+		ret.analyze(root, map[reflect.Type]bool{})
+	}
+	return ret
+}
+
+// analyze counts named struct type usages and detects recursive types reachable from t.
+func (g *generator) analyze(t reflect.Type, stack map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if p, ok := LookupInterface(t); ok {
+		for _, variant := range p.Variants {
+			g.analyze(variant.Type, stack)
 		}
-		return map[string]interface{}{"type": "object"}
+		return
 	}
-	visitedTypes[t] = true
-	visitedMu.Unlock()
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	if _, ok := customSchema(t); ok {
+		return
+	}
+	if stack[t] {
+		g.recursive[t] = true
+		return
+	}
+	g.counts[t]++
+	if g.counts[t] > 1 {
+		return
+	}
+	stack[t] = true
+	defer delete(stack, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !g.includeField(field) {
+			continue
+		}
+		if isEmbedded(field) {
+			// embedded structs are flattened, so only their fields count as usages
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			g.counts[embedded]--
+			g.analyze(embedded, stack)
+			continue
+		}
+		g.analyze(field.Type, stack)
+	}
+}
 
-	defer func() {
-		visitedMu.Lock()
-		delete(visitedTypes, t)
-		if first {
-			visitedTypes = nil
+// useDefinition reports whether t is emitted under $defs.
+func (g *generator) useDefinition(t reflect.Type) bool {
+	if t.Name() == "" || t == timeType {
+		return false
+	}
+	switch g.options.Definitions {
+	case DefinitionsRecursive:
+		return g.recursive[t]
+	case DefinitionsShared:
+		return g.recursive[t] || g.counts[t] > 1
+	}
+	return false
+}
+
+// reference returns a $ref schema for t, generating its definition on first use.
+// References to the root type point at the document root.
+func (g *generator) reference(t reflect.Type) map[string]interface{} {
+	if t == g.root {
+		return map[string]interface{}{"$ref": "#"}
+	}
+	ref, ok := g.refs[t]
+	if !ok {
+		name := g.definitionName(t)
+		ref = defsPrefix + name
+		g.refs[t] = ref
+		if g.defs == nil {
+			g.defs = map[string]map[string]interface{}{}
 		}
-		visitedMu.Unlock()
-	}()
+		g.defs[name] = g.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": ref}
+}
 
-	// Special handling for time.Time: treat as ISO 8601 string.
-	if t == reflect.TypeOf(time.Time{}) {
-		schema["type"] = "string"
-		schema["format"] = "date-time"
-		return schema
+var (
+	unsafeDefinitionChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	// typeArgumentPackage matches the import path qualifying a type argument, e.g. "github.com/x/pkg." in "Page[github.com/x/pkg.Item]".
+	typeArgumentPackage = regexp.MustCompile(`([A-Za-z0-9_.\-]+/)*[A-Za-z0-9_\-]+\.`)
+)
+
+// typeName returns the name of t with type arguments reduced to their unqualified names, e.g. "Page[Item]".
+func typeName(t reflect.Type) string {
+	name := t.Name()
+	open := strings.IndexByte(name, '[')
+	if open < 0 {
+		return name
+	}
+	return name[:open] + typeArgumentPackage.ReplaceAllString(name[open:], "")
+}
+
+// definitionName returns a unique $defs key for t, qualifying it with the package name on collision.
+// Generic instantiations are named after their type arguments, e.g. "Page_Item".
+func (g *generator) definitionName(t reflect.Type) string {
+	name := strings.Trim(unsafeDefinitionChars.ReplaceAllString(typeName(t), "_"), "_")
+	if other, ok := g.names[name]; ok && other != t {
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = unsafeDefinitionChars.ReplaceAllString(pkg, "_") + "." + name
+		for i := 2; g.names[name] != nil; i++ {
+			name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), i)
+		}
 	}
+	g.names[name] = t
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+	schema := map[string]interface{}{"type": "object"}
+	properties, required := g.structProperties(t)
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// buildJSONSchema constructs a JSON-schema fragment that represents the supplied Go reflect.Type.
+// The `inSlice` flag is an internal recursion marker: it is true when the type
+// currently being processed is the *element* of a slice/array. That allows the
+// algorithm to decide whether automatically added `nullable:true` (for pointer
+// types) should be kept or suppressed.
+// Types selected by the definitions mode are emitted as $ref pointers; otherwise
+// cycles are broken by returning an empty object schema on recursion.
+func (g *generator) buildJSONSchema(t reflect.Type, inSlice bool) map[string]interface{} {
+	schema := make(map[string]interface{})
 
 	// Handle pointer types.
 	if t.Kind() == reflect.Ptr {
 		// Unwrap pointer.
-		schema = buildJSONSchema(t.Elem(), inSlice, opts...)
+		schema = g.buildJSONSchema(t.Elem(), inSlice)
 		// Mark as nullable unless we are processing a slice element.
 		if !inSlice {
 			schema["nullable"] = true
@@ -71,6 +202,11 @@ func buildJSONSchema(t reflect.Type, inSlice bool, opts ...StructToPropertiesOpt
 		return schema
 	}
 
+	// Type overrides and self-describing types, e.g. time.Time as an ISO 8601 string.
+	if custom, ok := customSchema(t); ok {
+		return custom
+	}
+
 	switch t.Kind() {
 	case reflect.Bool:
 		schema["type"] = "boolean"
@@ -82,28 +218,33 @@ func buildJSONSchema(t reflect.Type, inSlice bool, opts ...StructToPropertiesOpt
 	case reflect.String:
 		schema["type"] = "string"
 	case reflect.Interface:
+		if p, ok := LookupInterface(t); ok {
+			return g.polymorphicSchema(p)
+		}
 		// Unconstrained value – same representation the MCP spec uses.
 		return schema
 	case reflect.Slice, reflect.Array:
 		schema["type"] = "array"
 		// When processing slice items, set inSlice = true.
-		schema["items"] = buildJSONSchema(t.Elem(), true, opts...)
+		schema["items"] = g.buildJSONSchema(t.Elem(), true)
 	case reflect.Map:
 		schema["type"] = "object"
 		// Open-ended objects when the map value is interface{}.
 		if t.Elem().Kind() == reflect.Interface {
 			schema["additionalProperties"] = true
 		} else {
-			schema["additionalProperties"] = buildJSONSchema(t.Elem(), false, opts...)
+			schema["additionalProperties"] = g.buildJSONSchema(t.Elem(), false)
 		}
 	case reflect.Struct:
-		// For structs, recursively convert their fields.
-		schema["type"] = "object"
-		properties, required := StructToProperties(t, opts...)
-		schema["properties"] = properties
-		if len(required) > 0 {
-			schema["required"] = required
+		if g.useDefinition(t) {
+			return g.reference(t)
 		}
+		// detect cyclic types: if this type is already being processed, break the cycle
+		if g.visiting[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		// For structs, recursively convert their fields.
+		return g.structSchema(t)
 	default:
 		// Fallback to string type.
 		schema["type"] = "string"
@@ -111,26 +252,27 @@ func buildJSONSchema(t reflect.Type, inSlice bool, opts ...StructToPropertiesOpt
 	return schema
 }
 
-// typeSchema is a package-internal helper that starts schema generation with
-// `inSlice == false` (i.e. at the “top level”). Callers inside this package
-// should use this instead of calling buildJSONSchema directly so they do not need to
-// know about the recursion flag.
-// use. It hides the recursion flag (`inSlice`) by always starting the walk in
-// "top-level" mode (inSlice == false).
-func typeSchema(t reflect.Type, opts ...StructToPropertiesOption) map[string]interface{} {
-	// typeSchema hides the recursion flag for callers at the top level.
-	return buildJSONSchema(t, false, opts...)
-}
-
 // StructToPropertiesOption defines an option for controlling field inclusion/exclusion in StructToProperties.
 type StructToPropertiesOption func(*structToPropertiesOptions)
 
 type structToPropertiesOptions struct {
-	SkipFieldHook   func(field reflect.StructField) bool
-	IsRequiredHook  func(field reflect.StructField) bool
-	FormatHook      func(field reflect.StructField) string
-	NullableHook    func(field reflect.StructField) *bool
-	DescriptionHook func(field reflect.StructField) string
+	SkipFieldHook      func(field reflect.StructField) bool
+	IsRequiredHook     func(field reflect.StructField) bool
+	FormatHook         func(field reflect.StructField) string
+	NullableHook       func(field reflect.StructField) *bool
+	DescriptionHook    func(field reflect.StructField) string
+	Definitions        DefinitionsMode
+	SourceDescriptions bool
+	Profile            Profile
+}
+
+// WithDefinitions sets which struct types are emitted under $defs and referenced with $ref.
+// ToolInputSchema.Load and ToolOutputSchema.Load default to DefinitionsRecursive; StructToProperties
+// defaults to DefinitionsInline since it cannot return the $defs block (use StructToSchema instead).
+func WithDefinitions(mode DefinitionsMode) StructToPropertiesOption {
+	return func(o *structToPropertiesOptions) {
+		o.Definitions = mode
+	}
 }
 
 // WithDescriptionHook create description hook  option
@@ -176,33 +318,81 @@ func WithNullableHook(hook func(field reflect.StructField) *bool) StructToProper
 // StructToProperties converts a struct type into MCP InputSchema properties and required fields.
 // It accepts optional StructToPropertiesOption to customize behavior (e.g., skipping fields, required logic, format overrides).
 func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolInputSchemaProperties, []string) {
-	var opt structToPropertiesOptions
+	properties, required, _ := StructToSchema(t, opts...)
+	return properties, required
+}
+
+// StructToSchema converts a struct type into properties, required fields and the $defs block
+// referenced by $ref pointers when WithDefinitions is enabled.
+// Results are cached per type and options, except for options with hooks; every call returns a copy.
+// Keywords whose tag values are invalid are omitted; ToolInputSchema.LoadType reports them as errors.
+func StructToSchema(t reflect.Type, opts ...StructToPropertiesOption) (ToolInputSchemaProperties, []string, map[string]map[string]interface{}) {
+	properties, required, defs, _ := structToSchema(t, opts)
+	return properties, required, defs
+}
+
+// structToSchema is StructToSchema also returning the errors of invalid struct tags.
+func structToSchema(t reflect.Type, opts []StructToPropertiesOption) (ToolInputSchemaProperties, []string, map[string]map[string]interface{}, error) {
+	var options structToPropertiesOptions
 	for _, o := range opts {
-		o(&opt)
+		o(&options)
+	}
+	key, cacheable := options.cacheKey(t)
+	if cacheable {
+		if entry, ok := cachedSchema(key); ok {
+			return entry.get()
+		}
+	}
+	g := newGenerator(t, options)
+	g.visiting[t] = true
+	properties, required := g.structProperties(t)
+	required = g.options.Profile.applyProfile(properties, required, g.defs)
+	err := errors.Join(g.errs...)
+	if cacheable {
+		cacheSchema(key, newSchemaEntry(properties, required, g.defs, err))
+	}
+	return properties, required, g.defs, err
+}
+
+// includeField reports whether a struct field takes part in the schema.
+func (g *generator) includeField(field reflect.StructField) bool {
+	if g.options.SkipFieldHook != nil && g.options.SkipFieldHook(field) {
+		return false
+	}
+	// Only process exported fields and, as encoding/json, embedded structs of unexported types.
+	if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct && isEmbedded(field)) {
+		return false
+	}
+	return field.Tag.Get("json") != "-" && field.Tag.Get("internal") == ""
+}
+
+// isEmbedded reports whether the field's properties are merged into the parent.
+func isEmbedded(field reflect.StructField) bool {
+	name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	inline := false
+	for _, option := range strings.Split(options, ",") {
+		inline = inline || option == "inline"
+	}
+	embedded := field.Type
+	if embedded.Kind() == reflect.Ptr {
+		embedded = embedded.Elem()
 	}
+	return embedded.Kind() == reflect.Struct && (inline || (field.Anonymous && (name == "" || name == field.Name)))
+}
 
+// structProperties converts the fields of a struct type into properties and required fields.
+func (g *generator) structProperties(t reflect.Type) (ToolInputSchemaProperties, []string) {
+	opt := g.options
 	properties := make(ToolInputSchemaProperties)
 	var required []string
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		if opt.SkipFieldHook != nil && opt.SkipFieldHook(field) {
-			continue
-		}
-		// Only process exported fields.
-		if !field.IsExported() {
+		if !g.includeField(field) {
 			continue
 		}
 		// Parse struct tags for json and format.
 		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
-			continue
-		}
-
-		isInternal := field.Tag.Get("internal")
-		if isInternal != "" {
-			continue
-		}
 		var fieldName string
 		var omitempty bool
 		var inline bool
@@ -237,7 +427,7 @@ func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolI
 			}
 			// Only inline struct types.
 			if embeddedType.Kind() == reflect.Struct {
-				childProps, childReq := StructToProperties(embeddedType, opts...)
+				childProps, childReq := g.structProperties(embeddedType)
 				// Merge child properties into parent, without overwriting existing keys.
 				for k, v := range childProps {
 					if _, exists := properties[k]; !exists {
@@ -257,7 +447,7 @@ func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolI
 		}
 
 		// Generate the field's JSON schema.
-		fieldSchema := typeSchema(field.Type, opts...)
+		fieldSchema := g.buildJSONSchema(field.Type, false)
 		// Determine format via hook or tag.
 		var fmtVal string
 		if opt.FormatHook != nil {
@@ -271,6 +461,10 @@ func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolI
 
 		if desc := field.Tag.Get("description"); desc != "" {
 			fieldSchema["description"] = desc
+		} else if _, ok := fieldSchema["description"]; !ok {
+			if desc := g.fieldDescription(t, field); desc != "" {
+				fieldSchema["description"] = desc
+			}
 		}
 		if opt.DescriptionHook != nil {
 			if desc := opt.DescriptionHook(field); desc != "" {
@@ -279,30 +473,10 @@ func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolI
 		}
 
 		if choice := field.Tag.Get("choice"); choice != "" {
-			re := regexp.MustCompile(`choice:"([^"]+)"`)
-			matches := re.FindAllStringSubmatch(string(field.Tag), -1)
-			var choices []string
-			for _, match := range matches {
-				choices = append(choices, match[1])
-			}
-			fieldSchema["enum"] = choices
+			fieldSchema["enum"] = tagValues(field.Tag, "choice")
 		}
-
-		// Handle default values for primitive types via `default:"..."` tag.
-		if def := field.Tag.Get("default"); def != "" {
-			switch field.Type.Kind() {
-			case reflect.String:
-				fieldSchema["default"] = def
-			case reflect.Bool:
-				if def == "true" || def == "false" {
-					fieldSchema["default"] = (def == "true")
-				}
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-				reflect.Float32, reflect.Float64:
-				// Keep as string; schema is typeless at this layer
-				fieldSchema["default"] = def
-			}
+		if err := applyValidationTags(fieldSchema, field); err != nil {
+			g.errs = append(g.errs, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err))
 		}
 
 		// Apply nullable override via hook if provided.
@@ -332,36 +506,95 @@ func StructToProperties(t reflect.Type, opts ...StructToPropertiesOption) (ToolI
 	return properties, required
 }
 
+// Load derives the schema from the type of v, see LoadType.
 func (s *ToolInputSchema) Load(v any, options ...StructToPropertiesOption) error {
-	t := reflect.TypeOf(v)
-	if t.Kind() == reflect.Pointer {
-		t = t.Elem()
-	}
-	if t.Kind() != reflect.Struct {
-		return fmt.Errorf("expected a struct type, got %s", t.Kind())
+	return s.LoadType(reflect.TypeOf(v), options...)
+}
+
+// LoadType derives the schema from a struct type, a map type with string keys or an interface type,
+// which, like a nil type, accepts any object; pointers are dereferenced.
+func (s *ToolInputSchema) LoadType(t reflect.Type, options ...StructToPropertiesOption) error {
+	root, err := loadObjectSchema(t, options)
+	if err != nil {
+		return err
 	}
-	properties, required := StructToProperties(t, options...)
-	s.Properties = properties
-	s.Required = required
+	s.Properties = root.properties
+	s.Required = root.required
+	s.Defs = root.defs
 	s.Type = "object"
+	s.Schema = root.schema
+	s.AdditionalProperties = root.additionalProperties
 	return nil
 }
 
+// Load derives the schema from the type of v, see LoadType.
 func (s *ToolOutputSchema) Load(v any, options ...StructToPropertiesOption) error {
-	t := reflect.TypeOf(v)
-	if t.Kind() == reflect.Pointer {
-		t = t.Elem()
-	}
-	if t.Kind() != reflect.Struct {
-		return fmt.Errorf("expected a struct type, got %s", t.Kind())
+	return s.LoadType(reflect.TypeOf(v), options...)
+}
+
+// LoadType derives the schema from a struct type, a map type with string keys or an interface type,
+// which, like a nil type, accepts any object; pointers are dereferenced.
+func (s *ToolOutputSchema) LoadType(t reflect.Type, options ...StructToPropertiesOption) error {
+	root, err := loadObjectSchema(t, options)
+	if err != nil {
+		return err
 	}
-	properties, required := StructToProperties(t, options...)
-	s.Properties = properties
-	s.Required = required
+	s.Properties = root.properties
+	s.Required = root.required
+	s.Defs = root.defs
 	s.Type = "object"
+	s.Schema = root.schema
+	s.AdditionalProperties = root.additionalProperties
 	return nil
 }
 
+// objectSchema holds the members of a generated root object schema.
+type objectSchema struct {
+	properties           ToolInputSchemaProperties
+	required             []string
+	defs                 map[string]map[string]interface{}
+	schema               *string
+	additionalProperties interface{}
+}
+
+func loadObjectSchema(t reflect.Type, options []StructToPropertiesOption) (*objectSchema, error) {
+	options = append([]StructToPropertiesOption{WithDefinitions(DefinitionsRecursive)}, options...)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	profile := profileOf(options)
+	ret := &objectSchema{}
+	ret.schema, ret.additionalProperties = profile.root()
+	switch {
+	case t == nil || t.Kind() == reflect.Interface:
+		ret.additionalProperties = nil
+	case t.Kind() == reflect.Struct:
+		var err error
+		if ret.properties, ret.required, ret.defs, err = structToSchema(t, options); err != nil {
+			return nil, err
+		}
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		var opts structToPropertiesOptions
+		for _, o := range options {
+			o(&opts)
+		}
+		g := newGenerator(t, opts)
+		value := g.buildJSONSchema(t.Elem(), false)
+		if err := errors.Join(g.errs...); err != nil {
+			return nil, err
+		}
+		profile.applyProfile(nil, nil, g.defs)
+		ret.additionalProperties = true
+		if len(value) > 0 {
+			ret.additionalProperties = profile.apply(value)
+		}
+		ret.defs = g.defs
+	default:
+		return nil, fmt.Errorf("expected a struct or map type, got %s", t.Kind())
+	}
+	return ret, nil
+}
+
 // NewCallToolRequestParams creates a new CallToolRequestParams instance from a command struct.
 func NewCallToolRequestParams[T any](name string, cmd T) (*CallToolRequestParams, error) {
 	results := &CallToolRequestParams{Name: name, Arguments: map[string]interface{}{}}
@@ -2,7 +2,9 @@ package schema
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Helper: get property as map (inner type is already map[string]interface{}).
@@ -27,6 +29,16 @@ func containsAll(slice []string, want ...string) bool {
 	return true
 }
 
+// loadSchema loads the input schema of v, failing the test on error.
+func loadSchema(t *testing.T, v interface{}, options ...StructToPropertiesOption) ToolInputSchema {
+	t.Helper()
+	var s ToolInputSchema
+	if err := s.Load(v, options...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s
+}
+
 // Test: anonymous embedded struct is flattened, requireds propagate when non-pointer.
 func TestStructToProperties_EmbeddedAnonymous(t *testing.T) {
 	type A struct {
@@ -200,3 +212,238 @@ func TestStructToProperties_InlineNoOverwrite(t *testing.T) {
 		t.Fatalf("expected x type integer from parent, got: %v", x["type"])
 	}
 }
+
+type treeNode struct {
+	Name     string      `json:"name"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+type address struct {
+	City string `json:"city"`
+}
+
+type filter struct {
+	Field string    `json:"field"`
+	And   []*filter `json:"and,omitempty"`
+	Or    []*filter `json:"or,omitempty"`
+}
+
+type searchInput struct {
+	Root     *treeNode `json:"root,omitempty"`
+	Filter   filter    `json:"filter"`
+	Home     address   `json:"home"`
+	Work     *address  `json:"work,omitempty"`
+	Fallback *address  `json:"fallback,omitempty"`
+}
+
+// Test: recursive types are emitted once under $defs and referenced with $ref.
+func TestToolInputSchema_LoadDefs(t *testing.T) {
+	s := loadSchema(t, &searchInput{})
+	root := prop(s.Properties, "root")
+	if root["$ref"] != "#/$defs/treeNode" || root["nullable"] != true {
+		t.Fatalf("expected root to reference treeNode, got: %#v", root)
+	}
+	node := s.Defs["treeNode"]
+	if node == nil {
+		t.Fatalf("expected treeNode definition, got: %#v", s.Defs)
+	}
+	children := node["properties"].(ToolInputSchemaProperties)["children"]
+	if items, _ := children["items"].(map[string]interface{}); items["$ref"] != "#/$defs/treeNode" {
+		t.Fatalf("expected children items to reference treeNode, got: %#v", children)
+	}
+	if s.Defs["filter"] == nil {
+		t.Fatalf("expected filter definition, got: %#v", s.Defs)
+	}
+	// non-recursive shared types stay inline by default
+	if _, ok := s.Defs["address"]; ok || prop(s.Properties, "home")["type"] != "object" {
+		t.Fatalf("expected address inline, got: %#v", s.Defs)
+	}
+}
+
+// Test: shared mode moves repeated types to $defs; inline mode keeps the legacy cycle break.
+func TestStructToSchema_DefinitionsMode(t *testing.T) {
+	props, _, defs := StructToSchema(reflect.TypeOf(searchInput{}), WithDefinitions(DefinitionsShared))
+	if defs["address"] == nil || prop(props, "home")["$ref"] != "#/$defs/address" || prop(props, "work")["$ref"] != "#/$defs/address" {
+		t.Fatalf("expected shared address definition, got: %#v %#v", props, defs)
+	}
+
+	props, _, defs = StructToSchema(reflect.TypeOf(searchInput{}))
+	if len(defs) != 0 {
+		t.Fatalf("expected no definitions in inline mode, got: %#v", defs)
+	}
+	if prop(props, "root")["type"] != "object" {
+		t.Fatalf("expected inline root object, got: %#v", prop(props, "root"))
+	}
+
+	// a recursive root type references the document root
+	s := loadSchema(t, treeNode{})
+	items, _ := prop(s.Properties, "children")["items"].(map[string]interface{})
+	if items["$ref"] != "#" || len(s.Defs) != 0 {
+		t.Fatalf("expected children to reference the root, got: %#v %#v", items, s.Defs)
+	}
+}
+
+type constrainedInput struct {
+	Limit    int       `json:"limit" minimum:"1" maximum:"100" multipleOf:"5"`
+	Ratio    float64   `json:"ratio" exclusiveMinimum:"0" exclusiveMaximum:"1.5"`
+	Name     string    `json:"name" minLength:"2" maxLength:"64" pattern:"^[a-z]+$" title:"Name" example:"alice" example:"bob"`
+	Tags     []string  `json:"tags" minItems:"1" maxItems:"5" uniqueItems:"true" pattern:"^#"`
+	Scores   []float64 `json:"scores" minimum:"0"`
+	Version  int       `json:"version" const:"2"`
+	Legacy   *bool     `json:"legacy,omitempty" deprecated:"true" const:"false"`
+	Invalid  string    `json:"invalid" pattern:"[" minLength:"x"`
+	Metadata []int     `json:"metadata" example:"[1,2]"`
+}
+
+// Test: validation keyword tags are emitted with type-appropriate values.
+func TestStructToProperties_ValidationTags(t *testing.T) {
+	props, _ := StructToProperties(reflect.TypeOf(constrainedInput{}))
+
+	limit := prop(props, "limit")
+	if limit["minimum"] != int64(1) || limit["maximum"] != int64(100) || limit["multipleOf"] != float64(5) {
+		t.Fatalf("unexpected limit schema: %#v", limit)
+	}
+	ratio := prop(props, "ratio")
+	if ratio["exclusiveMinimum"] != float64(0) || ratio["exclusiveMaximum"] != 1.5 {
+		t.Fatalf("unexpected ratio schema: %#v", ratio)
+	}
+	name := prop(props, "name")
+	if name["minLength"] != 2 || name["maxLength"] != 64 || name["pattern"] != "^[a-z]+$" || name["title"] != "Name" {
+		t.Fatalf("unexpected name schema: %#v", name)
+	}
+	if !reflect.DeepEqual(name["examples"], []interface{}{"alice", "bob"}) {
+		t.Fatalf("unexpected name examples: %#v", name["examples"])
+	}
+	tags := prop(props, "tags")
+	items, _ := tags["items"].(map[string]interface{})
+	if tags["minItems"] != 1 || tags["maxItems"] != 5 || tags["uniqueItems"] != true || items["pattern"] != "^#" {
+		t.Fatalf("unexpected tags schema: %#v", tags)
+	}
+	if items, _ := prop(props, "scores")["items"].(map[string]interface{}); items["minimum"] != float64(0) {
+		t.Fatalf("expected item minimum on scores, got: %#v", prop(props, "scores"))
+	}
+	if prop(props, "version")["const"] != int64(2) {
+		t.Fatalf("unexpected version const: %#v", prop(props, "version"))
+	}
+	legacy := prop(props, "legacy")
+	if legacy["deprecated"] != true || legacy["const"] != false {
+		t.Fatalf("unexpected legacy schema: %#v", legacy)
+	}
+	invalid := prop(props, "invalid")
+	if _, ok := invalid["pattern"]; ok {
+		t.Fatalf("expected invalid pattern to be ignored, got: %#v", invalid)
+	}
+	if _, ok := invalid["minLength"]; ok {
+		t.Fatalf("expected invalid minLength to be ignored, got: %#v", invalid)
+	}
+	if !reflect.DeepEqual(prop(props, "metadata")["examples"], []interface{}{[]interface{}{float64(1), float64(2)}}) {
+		t.Fatalf("unexpected metadata examples: %#v", prop(props, "metadata"))
+	}
+
+	var inputSchema ToolInputSchema
+	err := inputSchema.Load(constrainedInput{})
+	if err == nil || !strings.Contains(err.Error(), `constrainedInput.Invalid: invalid minLength "x"`) || !strings.Contains(err.Error(), `invalid pattern "["`) {
+		t.Fatalf("expected invalid tags to be reported, got: %v", err)
+	}
+}
+
+type defaultsInput struct {
+	Limit   int           `json:"limit" default:"20"`
+	Ratio   *float64      `json:"ratio" default:"0.5"`
+	Strict  bool          `json:"strict" default:"true"`
+	Mode    string        `json:"mode" default:"fast"`
+	Tags    []string      `json:"tags" default:"[\"a\"]"`
+	Timeout time.Duration `json:"timeout" default:"5s"`
+}
+
+type invalidDefaultsInput struct {
+	Limit  int      `json:"limit" default:"many"`
+	Strict bool     `json:"strict" default:"yes"`
+	Tags   []string `json:"tags" default:"a,b"`
+}
+
+// Test: default tags are typed by the field's kind, and invalid defaults are reported.
+func TestStructToProperties_Defaults(t *testing.T) {
+	props, _ := StructToProperties(reflect.TypeOf(defaultsInput{}))
+	expected := map[string]interface{}{
+		"limit":   int64(20),
+		"ratio":   0.5,
+		"strict":  true,
+		"mode":    "fast",
+		"tags":    []interface{}{"a"},
+		"timeout": "5s",
+	}
+	for name, value := range expected {
+		if actual := prop(props, name)["default"]; !reflect.DeepEqual(actual, value) {
+			t.Fatalf("unexpected %s default: %#v", name, actual)
+		}
+	}
+
+	var inputSchema ToolInputSchema
+	err := inputSchema.Load(invalidDefaultsInput{})
+	for _, expected := range []string{`Limit: invalid default "many"`, `Strict: invalid default "yes"`, `Tags: invalid default "a,b"`} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error containing %q, got: %v", expected, err)
+		}
+	}
+	// a cached schema reports the same error
+	if err2 := inputSchema.Load(invalidDefaultsInput{}); err2 == nil || err2.Error() != err.Error() {
+		t.Fatalf("expected cached error %v, got: %v", err, err2)
+	}
+}
+
+type page[T any] struct {
+	Items  []T    `json:"items"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type listRequest[T any] struct {
+	page[T]
+	Query string `json:"query"`
+}
+
+// Test: map, interface and generic inputs produce object schemas that decode back.
+func TestToolInputSchema_LoadType(t *testing.T) {
+	var s ToolInputSchema
+	if err := s.LoadType(reflect.TypeOf(map[string]address{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	additional, _ := s.AdditionalProperties.(map[string]interface{})
+	if s.Type != "object" || additional["type"] != "object" || len(s.Properties) != 0 {
+		t.Fatalf("unexpected map schema: %#v", s)
+	}
+	s = ToolInputSchema{}
+	if err := s.LoadType(reflect.TypeOf(map[string]interface{}{})); err != nil || s.AdditionalProperties != true {
+		t.Fatalf("unexpected open map schema: %#v %v", s, err)
+	}
+	s = ToolInputSchema{}
+	if err := s.LoadType(reflect.TypeOf((*interface{})(nil)).Elem()); err != nil || s.Type != "object" || s.AdditionalProperties != nil {
+		t.Fatalf("unexpected interface schema: %#v %v", s, err)
+	}
+	if err := s.LoadType(reflect.TypeOf([]string{})); err == nil {
+		t.Fatalf("expected error for a slice input")
+	}
+
+	s = loadSchema(t, &listRequest[treeNode]{})
+	items, _ := prop(s.Properties, "items")["items"].(map[string]interface{})
+	if items["$ref"] != "#/$defs/treeNode" || prop(s.Properties, "query") == nil || !reflect.DeepEqual(s.Required, []string{"items", "query"}) {
+		t.Fatalf("unexpected generic schema: %#v", s)
+	}
+	props, _, defs := StructToSchema(reflect.TypeOf(struct {
+		A page[filter] `json:"a"`
+		B page[filter] `json:"b"`
+	}{}), WithDefinitions(DefinitionsShared))
+	if _, ok := defs["page_filter"]; !ok || prop(props, "a")["$ref"] != "#/$defs/page_filter" {
+		t.Fatalf("unexpected generic definition: %#v", defs)
+	}
+
+	var input listRequest[filter]
+	arguments := map[string]interface{}{"query": "q", "items": []interface{}{map[string]interface{}{"field": "f"}}}
+	if err := DecodeArguments(arguments, &input, WithUnknownFieldsRejected()); err != nil || input.Items[0].Field != "f" || input.Query != "q" {
+		t.Fatalf("unexpected generic input: %#v %v", input, err)
+	}
+	var byName map[string]filter
+	if err := DecodeArguments(map[string]interface{}{"a": map[string]interface{}{"field": "x"}}, &byName); err != nil || byName["a"].Field != "x" {
+		t.Fatalf("unexpected map input: %#v %v", byName, err)
+	}
+}
@@ -5288,9 +5288,15 @@ func (j *ToolOutputSchema) UnmarshalJSON(value []byte) error {
 // Defaults to JSON Schema 2020-12 when no explicit $schema is provided.
 // Currently restricted to type: "object" at the root level.
 type ToolOutputSchema struct {
+	// Defs corresponds to the JSON schema field "$defs".
+	Defs map[string]map[string]interface{} `json:"$defs,omitempty" yaml:"$defs,omitempty" mapstructure:"$defs,omitempty"`
+
 	// Schema corresponds to the JSON schema field "$schema".
 	Schema *string `json:"$schema,omitempty" yaml:"$schema,omitempty" mapstructure:"$schema,omitempty"`
 
+	// Either a boolean or the schema of every property value.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty" mapstructure:"additionalProperties,omitempty"`
+
 	// Properties corresponds to the JSON schema field "properties".
 	Properties map[string]map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties,omitempty"`
 
@@ -5321,9 +5327,15 @@ func (j *ToolInputSchema) UnmarshalJSON(value []byte) error {
 
 // A JSON Schema object defining the expected parameters for the tool.
 type ToolInputSchema struct {
+	// Defs corresponds to the JSON schema field "$defs".
+	Defs map[string]map[string]interface{} `json:"$defs,omitempty" yaml:"$defs,omitempty" mapstructure:"$defs,omitempty"`
+
 	// Schema corresponds to the JSON schema field "$schema".
 	Schema *string `json:"$schema,omitempty" yaml:"$schema,omitempty" mapstructure:"$schema,omitempty"`
 
+	// Either a boolean or the schema of every property value.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty" mapstructure:"additionalProperties,omitempty"`
+
 	// Properties corresponds to the JSON schema field "properties".
 	Properties map[string]map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties,omitempty"`
 
@@ -2,7 +2,7 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -60,54 +60,109 @@ func (d *Registry) getToolHandler(name string) (ToolHandlerFunc, bool) {
 	return nil, false
 }
 
+// ToolOption customizes a tool registered with RegisterTool or RegisterNoArgTool.
+type ToolOption func(*toolOptions)
+
+type toolOptions struct {
+	schemaOptions []schema.StructToPropertiesOption
+	decodeOptions []schema.DecodeOption
+}
+
+// WithSchemaOptions sets options used to derive the input and output schemas, e.g. schema.WithProfile.
+func WithSchemaOptions(options ...schema.StructToPropertiesOption) ToolOption {
+	return func(o *toolOptions) {
+		o.schemaOptions = append(o.schemaOptions, options...)
+	}
+}
+
+// WithDecodeOptions sets options used to decode call arguments, e.g. schema.WithUnknownFieldsRejected or schema.WithDefaults.
+func WithDecodeOptions(options ...schema.DecodeOption) ToolOption {
+	return func(o *toolOptions) {
+		o.decodeOptions = append(o.decodeOptions, options...)
+	}
+}
+
 // RegisterTool derives JSON schemas from the generic I/O types and registers the tool.
-func RegisterTool[I any, O any](registry *Registry, name, description string, handler func(ctx context.Context, input I) (*schema.CallToolResult, *jsonrpc.Error)) error {
+// Call arguments are decoded into I directly from the request; decoding errors are reported as
+// invalid params with the offending argument path as error data.
+func RegisterTool[I any, O any](registry *Registry, name, description string, handler func(ctx context.Context, input I) (*schema.CallToolResult, *jsonrpc.Error), opts ...ToolOption) error {
 	var (
-		inVar     I
-		outVar    O
-		inSchema  schema.ToolInputSchema
-		outSchema schema.ToolOutputSchema
+		inSchema schema.ToolInputSchema
+		options  toolOptions
 	)
-
-	// Build input schema
-	sampleType := reflect.TypeOf(inVar)
-	if sampleType.Kind() == reflect.Pointer {
-		if err := inSchema.Load(inVar); err != nil {
-			return fmt.Errorf("failed to derive input schema for tool %s: %w", name, err)
-		}
-	} else {
-		if err := inSchema.Load(&inVar); err != nil {
-			return fmt.Errorf("failed to derive input schema for tool %s: %w", name, err)
-		}
+	for _, opt := range opts {
+		opt(&options)
 	}
-
-	// Build output schema
-	outputType := reflect.TypeOf(outVar)
-	if outputType.Kind() == reflect.Pointer {
-		if err := outSchema.Load(outVar); err != nil {
-			return fmt.Errorf("failed to derive output schema for tool %s: %w", name, err)
-		}
-	} else {
-		if err := outSchema.Load(&outVar); err != nil {
-			return fmt.Errorf("failed to derive output schema for tool %s: %w", name, err)
-		}
+	inputType := reflect.TypeOf((*I)(nil)).Elem()
+	if err := inSchema.LoadType(inputType, options.schemaOptions...); err != nil {
+		return fmt.Errorf("failed to derive input schema for tool %s: %w", name, err)
+	}
+	outSchema, err := outputSchema[O](options)
+	if err != nil {
+		return fmt.Errorf("failed to derive output schema for tool %s: %w", name, err)
 	}
 
 	// Wrap the typed handler so it matches ToolHandlerFunc.
 	wrapped := func(ctx context.Context, request *schema.CallToolRequest) (*schema.CallToolResult, *jsonrpc.Error) {
 		var input I
-		if args := request.Params.Arguments; args != nil {
-			data, err := json.Marshal(args)
-			if err != nil {
-				return nil, jsonrpc.NewError(jsonrpc.InvalidParams, err.Error(), nil)
-			}
-			if err := json.Unmarshal(data, &input); err != nil {
-				return nil, jsonrpc.NewError(jsonrpc.InvalidParams, err.Error(), nil)
-			}
+		if err := schema.DecodeArguments(request.Params.Arguments, &input, options.decodeOptions...); err != nil {
+			return nil, invalidArguments(err)
 		}
 		return handler(ctx, input)
 	}
 
-	registry.RegisterToolWithSchema(name, description, inSchema, &outSchema, wrapped)
+	if description == "" {
+		// fall back to the input type doc comment registered with schema.RegisterDescriptions
+		description = schema.TypeDescription(inputType)
+	}
+	registry.RegisterToolWithSchema(name, description, inSchema, outSchema, wrapped)
 	return nil
 }
+
+// RegisterNoArgTool registers a tool without input; its output schema is derived from O.
+// With schema.WithUnknownFieldsRejected in WithDecodeOptions, calls with arguments are rejected.
+func RegisterNoArgTool[O any](registry *Registry, name, description string, handler func(ctx context.Context) (*schema.CallToolResult, *jsonrpc.Error), opts ...ToolOption) error {
+	var options toolOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var inSchema schema.ToolInputSchema
+	if err := inSchema.LoadType(reflect.TypeOf(struct{}{}), options.schemaOptions...); err != nil {
+		return fmt.Errorf("failed to derive input schema for tool %s: %w", name, err)
+	}
+	outSchema, err := outputSchema[O](options)
+	if err != nil {
+		return fmt.Errorf("failed to derive output schema for tool %s: %w", name, err)
+	}
+	wrapped := func(ctx context.Context, request *schema.CallToolRequest) (*schema.CallToolResult, *jsonrpc.Error) {
+		var input struct{}
+		if err := schema.DecodeArguments(request.Params.Arguments, &input, options.decodeOptions...); err != nil {
+			return nil, invalidArguments(err)
+		}
+		return handler(ctx)
+	}
+	registry.RegisterToolWithSchema(name, description, inSchema, outSchema, wrapped)
+	return nil
+}
+
+// outputSchema derives the output schema of O; interface types such as any have none.
+func outputSchema[O any](options toolOptions) (*schema.ToolOutputSchema, error) {
+	outputType := reflect.TypeOf((*O)(nil)).Elem()
+	if outputType.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	ret := &schema.ToolOutputSchema{}
+	if err := ret.LoadType(outputType, options.schemaOptions...); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// invalidArguments converts an argument decoding error into an invalid params error.
+func invalidArguments(err error) *jsonrpc.Error {
+	var argumentErr *schema.ArgumentError
+	if errors.As(err, &argumentErr) && argumentErr.Path != "" {
+		return jsonrpc.NewError(jsonrpc.InvalidParams, err.Error(), map[string]interface{}{"path": argumentErr.Path})
+	}
+	return jsonrpc.NewError(jsonrpc.InvalidParams, err.Error(), nil)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/jsonrpc"
+	"github.com/viant/mcp-protocol/schema"
+)
+
+type searchFilter struct {
+	Field string  `json:"field"`
+	Value float64 `json:"value"`
+}
+
+type searchInput struct {
+	Query   string         `json:"query"`
+	Limit   int            `json:"limit,omitempty" default:"10"`
+	Mode    string         `json:"mode,omitempty" choice:"fast" choice:"full" default:"fast"`
+	Filters []searchFilter `json:"filters,omitempty"`
+}
+
+type searchOutput struct {
+	Summary string `json:"summary"`
+}
+
+func TestRegisterTool(t *testing.T) {
+	testCases := []struct {
+		name      string
+		options   []ToolOption
+		arguments map[string]interface{}
+		expected  *searchInput
+		errorPath string
+	}{
+		{
+			name:      "valid input",
+			arguments: map[string]interface{}{"query": "q", "limit": float64(5), "mode": "full", "filters": []interface{}{map[string]interface{}{"field": "a", "value": 1.5}}},
+			expected:  &searchInput{Query: "q", Limit: 5, Mode: "full", Filters: []searchFilter{{Field: "a", Value: 1.5}}},
+		},
+		{
+			name:      "unknown field ignored by default",
+			arguments: map[string]interface{}{"query": "q", "other": true},
+			expected:  &searchInput{Query: "q"},
+		},
+		{
+			name:      "unknown field in strict mode",
+			options:   []ToolOption{WithDecodeOptions(schema.WithUnknownFieldsRejected())},
+			arguments: map[string]interface{}{"query": "q", "filters": []interface{}{map[string]interface{}{"field": "a", "other": true}}},
+			errorPath: "filters[0].other",
+		},
+		{
+			name:      "type mismatch",
+			arguments: map[string]interface{}{"query": "q", "filters": []interface{}{map[string]interface{}{"field": "a"}, map[string]interface{}{"value": "x"}}},
+			errorPath: "filters[1].value",
+		},
+		{
+			name:      "defaults",
+			options:   []ToolOption{WithDecodeOptions(schema.WithDefaults())},
+			arguments: map[string]interface{}{"query": "q"},
+			expected:  &searchInput{Query: "q", Limit: 10, Mode: "fast"},
+		},
+		{
+			name:     "defaults without arguments",
+			options:  []ToolOption{WithDecodeOptions(schema.WithDefaults())},
+			expected: &searchInput{Limit: 10, Mode: "fast"},
+		},
+	}
+	for _, tc := range testCases {
+		registry := NewRegistry()
+		var actual *searchInput
+		err := RegisterTool[*searchInput, *searchOutput](registry, "search", "Searches documents.", func(ctx context.Context, input *searchInput) (*schema.CallToolResult, *jsonrpc.Error) {
+			actual = input
+			return &schema.CallToolResult{}, nil
+		}, tc.options...)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		handler, ok := registry.getToolHandler("search")
+		if !assert.True(t, ok, tc.name) {
+			continue
+		}
+		_, rpcErr := handler(context.Background(), &schema.CallToolRequest{Params: schema.CallToolRequestParams{Name: "search", Arguments: tc.arguments}})
+		if tc.errorPath != "" {
+			if assert.NotNil(t, rpcErr, tc.name) {
+				assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code, tc.name)
+				var data map[string]string
+				assert.NoError(t, json.Unmarshal(rpcErr.Data, &data), tc.name)
+				assert.Equal(t, tc.errorPath, data["path"], tc.name)
+			}
+			assert.Nil(t, actual, tc.name)
+			continue
+		}
+		assert.Nil(t, rpcErr, tc.name)
+		assert.Equal(t, tc.expected, actual, tc.name)
+	}
+}
+
+func TestRegisterTool_Schema(t *testing.T) {
+	registry := NewRegistry()
+	err := RegisterTool[*searchInput, *searchOutput](registry, "search", "", nil, WithSchemaOptions(schema.WithProfile(schema.ProfileStrict)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	tools := registry.ListRegisteredTools()
+	if !assert.Len(t, tools, 1) {
+		return
+	}
+	assert.Equal(t, false, tools[0].InputSchema.AdditionalProperties)
+	assert.ElementsMatch(t, []string{"query", "limit", "mode", "filters"}, tools[0].InputSchema.Required)
+	assert.NotNil(t, tools[0].OutputSchema)
+}
+
+func TestRegisterNoArgTool(t *testing.T) {
+	testCases := []struct {
+		name      string
+		options   []ToolOption
+		arguments map[string]interface{}
+		expectErr bool
+	}{
+		{name: "no arguments"},
+		{name: "arguments ignored", arguments: map[string]interface{}{"x": 1.0}},
+		{name: "arguments rejected in strict mode", options: []ToolOption{WithDecodeOptions(schema.WithUnknownFieldsRejected())}, arguments: map[string]interface{}{"x": 1.0}, expectErr: true},
+	}
+	for _, tc := range testCases {
+		registry := NewRegistry()
+		called := false
+		err := RegisterNoArgTool[*searchOutput](registry, "ping", "Checks the service.", func(ctx context.Context) (*schema.CallToolResult, *jsonrpc.Error) {
+			called = true
+			return &schema.CallToolResult{}, nil
+		}, tc.options...)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		handler, _ := registry.getToolHandler("ping")
+		_, rpcErr := handler(context.Background(), &schema.CallToolRequest{Params: schema.CallToolRequestParams{Name: "ping", Arguments: tc.arguments}})
+		assert.Equal(t, tc.expectErr, rpcErr != nil, tc.name)
+		assert.Equal(t, !tc.expectErr, called, tc.name)
+	}
+}
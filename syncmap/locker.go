@@ -0,0 +1,39 @@
+package syncmap
+
+import "sync"
+
+// Locker provides mutual exclusion per key; a key's mutex is dropped once no goroutine holds or waits for it.
+type Locker[K comparable] struct {
+	locks map[K]*keyLock
+	mux   sync.Mutex
+}
+
+type keyLock struct {
+	mux  sync.Mutex
+	refs int
+}
+
+// Lock locks key and returns the function that unlocks it.
+func (l *Locker[K]) Lock(k K) func() {
+	l.mux.Lock()
+	lock, ok := l.locks[k]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[k] = lock
+	}
+	lock.refs++
+	l.mux.Unlock()
+	lock.mux.Lock()
+	return func() {
+		lock.mux.Unlock()
+		l.mux.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, k)
+		}
+		l.mux.Unlock()
+	}
+}
+
+func NewLocker[K comparable]() *Locker[K] {
+	return &Locker[K]{locks: make(map[K]*keyLock)}
+}
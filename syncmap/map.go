@@ -29,7 +29,10 @@ func (m *Map[K, V]) Delete(k K) {
 	}
 }
 
+// Range calls f for each entry under the read lock; f must not modify the map.
 func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
 	for k, v := range m.m {
 		// call the function with the key and value
 		if !f(k, v) {